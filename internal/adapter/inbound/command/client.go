@@ -2,6 +2,7 @@ package command_inbound_adapter
 
 import (
 	"context"
+	"fmt"
 
 	"go-template/internal/domain"
 	"go-template/internal/model"
@@ -45,3 +46,35 @@ func (h *clientAdapter) StartUpsert(name string) {
 	}
 	log.WithContext(ctx).Info("client start upsert success")
 }
+
+func (h *clientAdapter) RotateKey(id int, gracePeriod string) {
+	ctx := activity.NewContext("command_client_rotate_key")
+	payload := model.ClientRotateInput{ID: id, GracePeriod: gracePeriod}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	result, err := h.domain.Client().RotateKey(ctx, payload)
+	if err != nil {
+		log.WithContext(ctx).Error("client rotate key error", err)
+		return
+	}
+
+	// The new key is printed once to the terminal and never logged
+	fmt.Println(result.BearerKey)
+	log.WithContext(ctx).Info("client rotate key success")
+}
+
+func (h *clientAdapter) IssueSigningSecret(id int) {
+	ctx := activity.NewContext("command_client_issue_signing_secret")
+	payload := model.ClientSigningSecretInput{ID: id}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	result, err := h.domain.Client().IssueSigningSecret(ctx, payload)
+	if err != nil {
+		log.WithContext(ctx).Error("client issue signing secret error", err)
+		return
+	}
+
+	// The secret is printed once to the terminal and never logged
+	fmt.Println(result.SigningSecret)
+	log.WithContext(ctx).Info("client issue signing secret success")
+}
@@ -2,6 +2,7 @@ package command_inbound_adapter
 
 import (
 	"context"
+	"strconv"
 
 	inbound_port "go-template/internal/port/inbound"
 	"go-template/utils/log"
@@ -20,6 +21,24 @@ func InitRoute(
 		case "start_upsert_client":
 			name := args[2]
 			port.Client().StartUpsert(name)
+		case "rotate_client_key":
+			id, err := strconv.Atoi(args[2])
+			if err != nil {
+				log.WithContext(ctx).Error("client id must be a number", err)
+				return
+			}
+			var gracePeriod string
+			if len(args) > 3 {
+				gracePeriod = args[3]
+			}
+			port.Client().RotateKey(id, gracePeriod)
+		case "issue_client_signing_secret":
+			id, err := strconv.Atoi(args[2])
+			if err != nil {
+				log.WithContext(ctx).Error("client id must be a number", err)
+				return
+			}
+			port.Client().IssueSigningSecret(id)
 		default:
 			log.WithContext(ctx).Info("command not found")
 		}
@@ -2,14 +2,16 @@ package gin_inbound_adapter
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/palantir/stacktrace"
 
 	"go-template/internal/domain"
 	"go-template/internal/model"
 	inbound_port "go-template/internal/port/inbound"
 	"go-template/utils/activity"
+	"go-template/utils/apperror"
 )
 
 type clientAdapter struct {
@@ -25,14 +27,11 @@ func NewClientAdapter(
 }
 
 func (h *clientAdapter) Upsert(c *gin.Context) {
-	ctx := activity.NewContext("http_client_upsert")
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_upsert")
 	var payload []model.ClientInput
 
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		_ = c.Error(apperror.ValidationFrom(err))
 		return
 	}
 
@@ -40,10 +39,7 @@ func (h *clientAdapter) Upsert(c *gin.Context) {
 
 	results, err := h.domain.Client().Upsert(ctx, payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -54,43 +50,35 @@ func (h *clientAdapter) Upsert(c *gin.Context) {
 }
 
 func (h *clientAdapter) Find(c *gin.Context) {
-	ctx := activity.NewContext("http_client_find_by_filter")
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_find_by_filter")
 	var payload model.ClientFilter
 
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		_ = c.Error(apperror.ValidationFrom(err))
 		return
 	}
 
 	ctx = activity.WithPayload(ctx, payload)
 
-	results, err := h.domain.Client().FindByFilter(ctx, payload)
+	results, page, err := h.domain.Client().FindByFilter(ctx, payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, model.Response{
 		Success: true,
 		Data:    results,
+		Page:    &page,
 	})
 }
 
 func (h *clientAdapter) Delete(c *gin.Context) {
-	ctx := activity.NewContext("http_client_delete_by_filter")
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_delete_by_filter")
 	var payload model.ClientFilter
 
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		_ = c.Error(apperror.ValidationFrom(err))
 		return
 	}
 
@@ -98,14 +86,240 @@ func (h *clientAdapter) Delete(c *gin.Context) {
 
 	err := h.domain.Client().DeleteByFilter(ctx, payload)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+	})
+}
+
+func (h *clientAdapter) Rotate(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_rotate_key")
+	var payload model.ClientRotateInput
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	result, err := h.domain.Client().RotateKey(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) Revoke(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_revoke_by_filter")
+	var payload model.ClientFilter
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	err := h.domain.Client().RevokeByFilter(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+	})
+}
+
+func (h *clientAdapter) SigningSecret(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_issue_signing_secret")
+	var payload model.ClientSigningSecretInput
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	result, err := h.domain.Client().IssueSigningSecret(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) List(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_list")
+	var payload model.ClientFilter
+
+	if err := c.ShouldBindQuery(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	results, page, err := h.domain.Client().FindByFilter(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    results,
+		Page:    &page,
+	})
+}
+
+func (h *clientAdapter) Get(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_get")
+	id, err := clientID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, id)
+
+	result, err := h.domain.Client().FindByID(ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) Create(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_create")
+	var payload model.ClientInput
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	result, err := h.domain.Client().Create(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Location", clientLocation(c, result.ID))
+	c.JSON(http.StatusCreated, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) Update(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_update")
+	id, err := clientID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var payload model.ClientInput
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	result, err := h.domain.Client().Update(ctx, id, payload)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, model.Response{
 		Success: true,
+		Data:    result,
 	})
 }
+
+func (h *clientAdapter) Patch(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_patch")
+	id, err := clientID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var payload model.ClientPatch
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		_ = c.Error(apperror.ValidationFrom(err))
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	result, err := h.domain.Client().Patch(ctx, id, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) Remove(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_client_delete")
+	id, err := clientID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ctx = activity.WithPayload(ctx, id)
+
+	err = h.domain.Client().DeleteByID(ctx, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// clientID reads the :id path parameter of the client resource routes
+func clientID(c *gin.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		return 0, apperror.NewInvalidFields([]apperror.FieldError{
+			{Field: "id", Rule: "gt", Message: "must be a positive integer"},
+		})
+	}
+	return id, nil
+}
+
+// clientLocation is the URL of a client resource, relative to the collection it was created in
+func clientLocation(c *gin.Context, id int) string {
+	return strings.TrimSuffix(c.Request.URL.Path, "/") + "/" + strconv.Itoa(id)
+}
@@ -2,11 +2,13 @@ package gin_inbound_adapter_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -17,7 +19,9 @@ import (
 	gin_inbound_adapter "go-template/internal/adapter/inbound/gin"
 	"go-template/internal/domain"
 	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
 	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apperror"
 )
 
 func TestClientAdapter(t *testing.T) {
@@ -31,23 +35,41 @@ func TestClientAdapter(t *testing.T) {
 		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
 
 		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockClientScopeDatabasePort := mock_outbound_port.NewMockClientScopeDatabasePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientScope().Return(mockClientScopeDatabasePort).AnyTimes()
+		mockClientScopeDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientScope{{ClientID: 1, Scope: "clients:read"}}, nil).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mock_outbound_port.NewMockClientMessagePort(mockCtrl)).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
 		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		inTransaction := func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+			return txFunc(mockDatabasePort)
+		}
 		adapter := gin_inbound_adapter.NewAdapter(dom)
 
 		// Set Gin to test mode
 		gin.SetMode(gin.TestMode)
 		router := gin.New()
+		router.Use(adapter.Middleware().ErrorHandler())
 		router.POST("/client-upsert", adapter.Client().Upsert)
 		router.POST("/client-find", adapter.Client().Find)
 		router.POST("/client-delete", adapter.Client().Delete)
+		router.POST("/client-rotate", adapter.Client().Rotate)
+		router.POST("/client-revoke", adapter.Client().Revoke)
+		router.POST("/client-signing-secret", adapter.Client().SigningSecret)
+		router.GET("/v1/clients", adapter.Client().List)
+		router.POST("/v1/clients", adapter.Client().Create)
+		router.GET("/v1/clients/:id", adapter.Client().Get)
+		router.PUT("/v1/clients/:id", adapter.Client().Update)
+		router.PATCH("/v1/clients/:id", adapter.Client().Patch)
+		router.DELETE("/v1/clients/:id", adapter.Client().Remove)
 
 		inputs := []model.ClientInput{
 			{Name: "Test Client"},
@@ -71,8 +93,10 @@ func TestClientAdapter(t *testing.T) {
 
 		Convey("Upsert", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				body, _ := json.Marshal(inputs)
 				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
@@ -97,10 +121,32 @@ func TestClientAdapter(t *testing.T) {
 				router.ServeHTTP(w, req)
 
 				So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "validation_error")
+			})
+
+			Convey("Invalid input", func() {
+				body, _ := json.Marshal([]model.ClientInput{{Name: "Test Client", BearerKey: "weak"}})
+				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "validation_error")
+				So(result.Details, ShouldHaveLength, 1)
+				So(result.Details[0].Field, ShouldEqual, "[0].bearer_key")
 			})
 
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(errors.New("database error")).Times(1)
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
 
 				body, _ := json.Marshal(inputs)
 				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
@@ -109,13 +155,190 @@ func TestClientAdapter(t *testing.T) {
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 
-				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "unavailable")
+				So(result.Error, ShouldNotContainSubstring, "database error")
+			})
+			Convey("Conflict", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(apperror.NewConflict("certificate_subject is already used by another client")).Times(1)
+
+				body, _ := json.Marshal(inputs)
+				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusConflict)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "conflict")
+				So(result.Error, ShouldEqual, "certificate_subject is already used by another client")
+			})
+		})
+
+		Convey("Rotate", func() {
+			Convey("Success", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+					return txFunc(mockDatabasePort)
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().UpdateKey(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				body, _ := json.Marshal(model.ClientRotateInput{ID: 1, GracePeriod: "1h"})
+				req := httptest.NewRequest(http.MethodPost, "/client-rotate", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+
+				var result struct {
+					Success bool         `json:"success"`
+					Data    model.Client `json:"data"`
+				}
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Success, ShouldBeTrue)
+				So(result.Data.BearerKey, ShouldNotBeEmpty)
+			})
+
+			Convey("Invalid JSON", func() {
+				req := httptest.NewRequest(http.MethodPost, "/client-rotate", bytes.NewReader([]byte("invalid")))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Domain error", func() {
+				body, _ := json.Marshal(model.ClientRotateInput{})
+				req := httptest.NewRequest(http.MethodPost, "/client-rotate", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "validation_error")
+				So(result.Error, ShouldEqual, "id is empty")
+			})
+
+			Convey("Client not found", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+					return txFunc(mockDatabasePort)
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(nil, nil).Times(1)
+
+				body, _ := json.Marshal(model.ClientRotateInput{ID: 404})
+				req := httptest.NewRequest(http.MethodPost, "/client-rotate", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Code, ShouldEqual, "not_found")
+			})
+		})
+
+		Convey("Revoke", func() {
+			Convey("Success", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().RevokeByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				body, _ := json.Marshal(filter)
+				req := httptest.NewRequest(http.MethodPost, "/client-revoke", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Domain error", func() {
+				body, _ := json.Marshal(model.ClientFilter{})
+				req := httptest.NewRequest(http.MethodPost, "/client-revoke", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("SigningSecret", func() {
+			Convey("Success", func() {
+				os.Setenv("SECRET_ENCRYPTION_KEY", "test-encryption-key")
+				defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().UpdateSigningSecret(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				body, _ := json.Marshal(model.ClientSigningSecretInput{ID: 1})
+				req := httptest.NewRequest(http.MethodPost, "/client-signing-secret", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+
+				var result struct {
+					Success bool         `json:"success"`
+					Data    model.Client `json:"data"`
+				}
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Success, ShouldBeTrue)
+				So(result.Data.SigningSecret, ShouldNotBeEmpty)
+			})
+
+			Convey("Invalid JSON", func() {
+				req := httptest.NewRequest(http.MethodPost, "/client-signing-secret", bytes.NewReader([]byte("invalid")))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Domain error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				body, _ := json.Marshal(model.ClientSigningSecretInput{ID: 1})
+				req := httptest.NewRequest(http.MethodPost, "/client-signing-secret", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
 			})
 		})
 
 		Convey("Find", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
@@ -130,6 +353,7 @@ func TestClientAdapter(t *testing.T) {
 				var result model.Response
 				json.Unmarshal(respBody, &result)
 				So(result.Success, ShouldBeTrue)
+				So(result.Page.Total, ShouldEqual, 1)
 			})
 
 			Convey("Invalid JSON", func() {
@@ -143,7 +367,7 @@ func TestClientAdapter(t *testing.T) {
 			})
 
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
@@ -152,13 +376,15 @@ func TestClientAdapter(t *testing.T) {
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 
-				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
 			})
 		})
 
 		Convey("Delete", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-delete", bytes.NewReader(body))
@@ -186,7 +412,8 @@ func TestClientAdapter(t *testing.T) {
 			})
 
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-delete", bytes.NewReader(body))
@@ -195,7 +422,175 @@ func TestClientAdapter(t *testing.T) {
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 
-				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+
+		Convey("Resource", func() {
+			Convey("List filters by query string", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.IDs, ShouldResemble, []int{1, 2})
+					So(filter.Names, ShouldResemble, []string{"Test Client"})
+					So(filter.Search, ShouldEqual, "test")
+					So(filter.Sort, ShouldEqual, "-id")
+					So(filter.Limit, ShouldEqual, 2)
+					return []model.Client{{ID: 2}, {ID: 1}}, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(2), nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients?ids=1&ids=2&names=Test+Client&search=test&sort=-id&limit=1", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Page.Total, ShouldEqual, 2)
+				So(result.Page.NextCursor, ShouldEqual, model.EncodeCursor(2))
+			})
+
+			Convey("List ignores bearer keys in the query string", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.BearerKeyHashes, ShouldBeEmpty)
+					return nil, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients?bearer_keys=test-bearer-key", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("List with an invalid limit", func() {
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients?limit=1000", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("Get", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}, false).Return(outputs, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients/1", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Get unknown client", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(nil, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients/2", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+
+			Convey("Get with an invalid id", func() {
+				req := httptest.NewRequest(http.MethodGet, "/v1/clients/abc", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+				var result model.Response
+				json.Unmarshal(w.Body.Bytes(), &result)
+				So(result.Details[0].Field, ShouldEqual, "id")
+			})
+
+			Convey("Create", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				body, _ := json.Marshal(inputs[0])
+				req := httptest.NewRequest(http.MethodPost, "/v1/clients", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusCreated)
+				So(w.Header().Get("Location"), ShouldEqual, "/v1/clients/1")
+			})
+
+			Convey("Create conflict", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(apperror.NewConflict("bearer_key or certificate_subject is already used by another client")).Times(1)
+
+				body, _ := json.Marshal(inputs[0])
+				req := httptest.NewRequest(http.MethodPost, "/v1/clients", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("Update", func() {
+				// Stored rows never carry the plaintext key
+				stored := outputs[0]
+				stored.BearerKey = ""
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return([]model.Client{stored}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientScopeDatabasePort.EXPECT().Replace(gomock.Any(), 1, []string{}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				body, _ := json.Marshal(model.ClientInput{Name: "Renamed Client"})
+				req := httptest.NewRequest(http.MethodPut, "/v1/clients/1", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Patch unknown client", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(nil, nil).Times(1)
+
+				body, _ := json.Marshal(map[string]int{"rate_limit": 10})
+				req := httptest.NewRequest(http.MethodPatch, "/v1/clients/2", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+
+			Convey("Delete", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodDelete, "/v1/clients/1", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusNoContent)
+			})
+
+			Convey("Delete unknown client", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(nil, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodDelete, "/v1/clients/2", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, http.StatusNotFound)
 			})
 		})
 	})
@@ -0,0 +1,48 @@
+package gin_inbound_adapter
+
+import (
+	_ "embed"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	inbound_port "go-template/internal/port/inbound"
+)
+
+// swaggerInitializer replaces the one shipped with Swagger UI, which opens the petstore example
+//
+//go:embed swagger/swagger-initializer.js
+var swaggerInitializer []byte
+
+type docsAdapter struct{}
+
+func NewDocsAdapter() inbound_port.DocsHttpPort {
+	return &docsAdapter{}
+}
+
+// OpenAPI serves the OpenAPI document of every route registered by InitRoute
+func (h *docsAdapter) OpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, openAPIDocument())
+}
+
+// SwaggerUI serves the embedded Swagger UI files under /docs/*filepath
+func (h *docsAdapter) SwaggerUI(c *gin.Context) {
+	file := strings.TrimPrefix(c.Param("filepath"), "/")
+	switch file {
+	case "", "index.html":
+		// Served directly; http.FileServer redirects index.html requests to the directory
+		index, err := fs.ReadFile(swaggerFiles.FS, "index.html")
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", index)
+	case "swagger-initializer.js":
+		c.Data(http.StatusOK, "text/javascript; charset=utf-8", swaggerInitializer)
+	default:
+		c.FileFromFS(file, http.FS(swaggerFiles.FS))
+	}
+}
@@ -0,0 +1,28 @@
+package gin_inbound_adapter
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewEngine builds the Gin engine with the proxies it may take the client IP
+// from. Lockout and rate limiting key on that IP, so X-Forwarded-For is only
+// honoured from SERVER_TRUSTED_PROXIES and ignored when it is empty
+func NewEngine() (*gin.Engine, error) {
+	app := gin.Default()
+
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("SERVER_TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	err := app.SetTrustedProxies(proxies)
+	if err != nil {
+		return nil, err
+	}
+	return app, nil
+}
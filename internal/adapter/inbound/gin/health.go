@@ -0,0 +1,49 @@
+package gin_inbound_adapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	inbound_port "go-template/internal/port/inbound"
+	"go-template/utils/activity"
+)
+
+type healthAdapter struct {
+	domain domain.Domain
+}
+
+func NewHealthAdapter(
+	domain domain.Domain,
+) inbound_port.HealthHttpPort {
+	return &healthAdapter{
+		domain: domain,
+	}
+}
+
+func (h *healthAdapter) Liveness(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_health_liveness")
+	result := h.domain.Health().Liveness(ctx)
+
+	c.JSON(http.StatusOK, model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *healthAdapter) Readiness(c *gin.Context) {
+	ctx := activity.NewContextFrom(c.Request.Context(), "http_health_readiness")
+	result := h.domain.Health().Readiness(ctx)
+
+	status := http.StatusOK
+	if !result.IsUp() {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, model.Response{
+		Success: result.IsUp(),
+		Data:    result,
+	})
+}
@@ -1,22 +1,48 @@
 package gin_inbound_adapter
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	"go-template/internal/domain"
 	"go-template/internal/model"
 	inbound_port "go-template/internal/port/inbound"
+	"go-template/utils"
 	"go-template/utils/activity"
-	"go-template/utils/jwt"
+	"go-template/utils/apperror"
+	"go-template/utils/log"
+	"go-template/utils/metrics"
+	"go-template/utils/mtls"
+	"go-template/utils/ratelimit"
+	"go-template/utils/signature"
+	"go-template/utils/tracing"
 )
 
 const (
+	transactionIDHeader = "X-Transaction-ID"
 	authorizationHeader = "Authorization"
 	bearerPrefix        = "Bearer "
 	bearerPrefixLen     = 7
+	principalKey        = "principal"
+
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
 )
 
 type middlewareAdapter struct {
@@ -31,25 +57,189 @@ func NewMiddlewareAdapter(
 	}
 }
 
+// InternalAuth identifies the caller by one of the named internal keys and
+// only lets it through to the routes that key was granted
 func (h *middlewareAdapter) InternalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader(authorizationHeader)
-		var bearerToken string
+		ctx := activity.NewContextFrom(c.Request.Context(), "http_internal_auth")
+		principal, credential, err := h.domain.Auth().AuthenticateInternal(ctx, model.Credentials{
+			IP:          c.ClientIP(),
+			BearerToken: bearerToken(c),
+		})
+		if err != nil {
+			abortWithError(ctx, c, err)
+			return
+		}
+
+		setPrincipal(c, principal)
+		ctx = model.ContextWithPrincipal(ctx, principal)
+
+		route := c.FullPath()
+		if !credential.Allows(c.Request.Method, route) {
+			log.WithContext(activity.WithResult(ctx, model.InternalAudit{
+				Credential: credential.Name,
+				Method:     c.Request.Method,
+				Route:      route,
+				Status:     http.StatusForbidden,
+			})).Warn("internal request denied")
+			abortWithError(ctx, c, apperror.NewForbidden("Forbidden"))
+			return
+		}
+
+		c.Next()
+
+		log.WithContext(activity.WithResult(ctx, model.InternalAudit{
+			Credential: credential.Name,
+			Method:     c.Request.Method,
+			Route:      route,
+			Status:     c.Writer.Status(),
+		})).Info("internal request")
+	}
+}
+
+// ClientAuth identifies the caller with the configured AUTH_DRIVER
+func (h *middlewareAdapter) ClientAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := activity.NewContextFrom(c.Request.Context(), "http_client_auth")
+		credentials := model.Credentials{
+			IP:                    c.ClientIP(),
+			BearerToken:           bearerToken(c),
+			CertificateIdentities: certificateIdentities(c),
+		}
+		// Only the HMAC driver needs the body before the handler runs
+		if os.Getenv("AUTH_DRIVER") == "hmac" {
+			signed, ok := signedRequest(c)
+			if !ok {
+				return
+			}
+			credentials.Signed = &signed
+		}
+
+		principal, err := h.domain.Auth().AuthenticateClient(ctx, credentials)
+		if err != nil {
+			abortWithError(ctx, c, err)
+			return
+		}
+
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// SignatureAuth authenticates clients that sign each request with their HMAC
+// signing secret instead of sending a bearer key
+func (h *middlewareAdapter) SignatureAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := activity.NewContextFrom(c.Request.Context(), "http_signature_auth")
+		signed, ok := signedRequest(c)
+		if !ok {
+			return
+		}
 
-		if len(authHeader) > bearerPrefixLen && authHeader[:bearerPrefixLen] == bearerPrefix {
-			bearerToken = authHeader[bearerPrefixLen:]
+		principal, err := h.domain.Auth().AuthenticateSignature(ctx, model.Credentials{
+			IP:     c.ClientIP(),
+			Signed: &signed,
+		})
+		if err != nil {
+			abortWithError(ctx, c, err)
+			return
 		}
 
-		if bearerToken == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized",
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader(authorizationHeader)
+	if len(authHeader) > bearerPrefixLen && authHeader[:bearerPrefixLen] == bearerPrefix {
+		return authHeader[bearerPrefixLen:]
+	}
+	return ""
+}
+
+// certificateIdentities reads the client certificate verified during the TLS handshake
+func certificateIdentities(c *gin.Context) []string {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return mtls.Identities(c.Request.TLS.VerifiedChains[0][0])
+}
+
+// signedRequest gathers what the signature covers. It aborts the request and
+// reports false when the body cannot be read
+func signedRequest(c *gin.Context) (model.SignedRequest, bool) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		// The body is read before the signature is checked, so it is capped
+		// to keep unauthenticated callers from holding large bodies in memory
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, signatureMaxBodyBytes()))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, model.Response{
+					Success: false,
+					Error:   "Request Entity Too Large",
+				})
+				return model.SignedRequest{}, false
+			}
+
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.Response{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return model.SignedRequest{}, false
+		}
+		// Handlers still need to bind the body
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return model.SignedRequest{
+		ClientID:   c.GetHeader(signature.ClientIDHeader),
+		Method:     c.Request.Method,
+		RequestURI: c.Request.URL.RequestURI(),
+		Timestamp:  c.GetHeader(signature.TimestampHeader),
+		Nonce:      c.GetHeader(signature.NonceHeader),
+		Signature:  c.GetHeader(signature.SignatureHeader),
+		Body:       body,
+	}, true
+}
+
+// signatureMaxBodyBytes is the largest body a signed request may carry,
+// REQUEST_SIGNATURE_MAX_BODY_BYTES or 1 MiB
+func signatureMaxBodyBytes() int64 {
+	if limit := utils.GetEnvInt("REQUEST_SIGNATURE_MAX_BODY_BYTES", 1<<20); limit > 0 {
+		return int64(limit)
+	}
+	return 1 << 20
+}
+
+// setPrincipal exposes the caller to later handlers through the Gin context and
+// to the domain through the request context
+func setPrincipal(c *gin.Context, principal model.Principal) {
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(model.ContextWithPrincipal(c.Request.Context(), principal))
+}
+
+// RequireScopes only lets through callers whose principal, set by ClientAuth,
+// was granted every listed scope
+func (h *middlewareAdapter) RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(principalKey)
+		principal, isPrincipal := value.(model.Principal)
+		if !ok || !isPrincipal {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
+				Success: false,
+				Error:   "Unauthorized",
 			})
 			return
 		}
 
-		if bearerToken != os.Getenv("INTERNAL_KEY") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized",
+		if !principal.HasScopes(scopes...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.Response{
+				Success: false,
+				Error:   "Forbidden: requires scopes " + strings.Join(scopes, " "),
 			})
 			return
 		}
@@ -58,55 +248,118 @@ func (h *middlewareAdapter) InternalAuth() gin.HandlerFunc {
 	}
 }
 
-func (h *middlewareAdapter) ClientAuth() gin.HandlerFunc {
+// RateLimit limits each caller, identified by the principal set by ClientAuth
+// or by its IP when there is none, and reports the limit in RateLimit-* headers
+func (h *middlewareAdapter) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx := activity.NewContext("http_client_auth")
-		authHeader := c.GetHeader(authorizationHeader)
-		var bearerToken string
+		ctx := activity.NewContextFrom(c.Request.Context(), "http_rate_limit")
 
-		if len(authHeader) > bearerPrefixLen && authHeader[:bearerPrefixLen] == bearerPrefix {
-			bearerToken = authHeader[bearerPrefixLen:]
+		key, limit := "ip:"+c.ClientIP(), 0
+		if principal, ok := c.Value(principalKey).(model.Principal); ok {
+			key, limit = principal.Source+":"+principal.Subject, principal.RateLimit
 		}
 
-		if bearerToken == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
+		result := h.domain.RateLimit().Allow(ctx, key, limit)
+		if result.Limit > 0 {
+			c.Header(rateLimitLimitHeader, strconv.Itoa(result.Limit))
+			c.Header(rateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+			c.Header(rateLimitResetHeader, strconv.Itoa(ratelimit.Seconds(result.ResetAfter)))
+		}
+
+		if !result.Allowed {
+			c.Header(retryAfterHeader, strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, model.Response{
 				Success: false,
-				Error:   "Unauthorized",
+				Error:   "Too Many Requests",
 			})
 			return
 		}
 
-		authDriver := os.Getenv("AUTH_DRIVER")
-		if authDriver == "jwt" {
-			jwksURL := os.Getenv("AUTH_JWKS_URL")
+		c.Next()
+	}
+}
 
-			_, err := jwt.ValidateJWTWithURL(bearerToken, jwksURL)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-					Success: false,
-					Error:   "Unauthorized: " + err.Error(),
-				})
-				return
-			}
-		} else {
-			exists, err := h.domain.Client().IsExists(ctx, bearerToken)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusInternalServerError, model.Response{
-					Success: false,
-					Error:   err.Error(),
-				})
-				return
-			}
+// ErrorHandler renders the error a handler attached with c.Error, so every
+// route reports failures with the same status mapping and response shape
+func (h *middlewareAdapter) ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
 
-			if !exists {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, model.Response{
-					Success: false,
-					Error:   "Unauthorized",
-				})
-				return
-			}
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		ctx := activity.NewContextFrom(c.Request.Context(), "http_error")
+		abortWithError(ctx, c, c.Errors.Last().Err)
+	}
+}
+
+// abortWithError maps err to its HTTP status and machine readable code. Server
+// side failures are logged in full, callers only get a generic message
+func abortWithError(ctx context.Context, c *gin.Context, err error) {
+	status := apperror.HTTPStatus(err)
+	if status >= http.StatusInternalServerError {
+		log.WithContext(ctx).Error("http request error", err)
+	}
+	if retryAfter := apperror.RetryAfter(err); retryAfter > 0 {
+		c.Header(retryAfterHeader, strconv.Itoa(ratelimit.Seconds(retryAfter)))
+	}
+
+	c.AbortWithStatusJSON(status, model.Response{
+		Success: false,
+		Error:   apperror.Message(err),
+		Code:    apperror.Code(err),
+		Details: apperror.Details(err),
+	})
+}
+
+func (h *middlewareAdapter) Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// Use the route template so path parameters don't explode label cardinality
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
 		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), start)
+	}
+}
+
+// Tracing starts the server span for the request and seeds the activity
+// transaction ID, reusing the caller's X-Transaction-ID when it is a valid UUID
+func (h *middlewareAdapter) Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := tracing.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
 
+		trxID := c.GetHeader(transactionIDHeader)
+		if _, err := uuid.Parse(trxID); err != nil {
+			trxID = uuid.NewString()
+		}
+		ctx = activity.WithTransactionID(ctx, trxID)
+		c.Header(transactionIDHeader, trxID)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("transaction_id", trxID),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
 	}
 }
@@ -1,10 +1,17 @@
 package gin_inbound_adapter_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -15,6 +22,12 @@ import (
 	"go-template/internal/domain"
 	"go-template/internal/model"
 	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/activity"
+	"go-template/utils/apikey"
+	"go-template/utils/internalkey"
+	"go-template/utils/metrics"
+	"go-template/utils/secretbox"
+	"go-template/utils/signature"
 )
 
 func TestMiddlewareAdapter(t *testing.T) {
@@ -28,15 +41,32 @@ func TestMiddlewareAdapter(t *testing.T) {
 		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
 
 		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockClientScopeDatabasePort := mock_outbound_port.NewMockClientScopeDatabasePort(mockCtrl)
 		mockClientMessagePort := mock_outbound_port.NewMockClientMessagePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
+		mockTokenCachePort := mock_outbound_port.NewMockTokenCachePort(mockCtrl)
+		mockNonceCachePort := mock_outbound_port.NewMockNonceCachePort(mockCtrl)
+		mockRateLimitCachePort := mock_outbound_port.NewMockRateLimitCachePort(mockCtrl)
+		mockLockoutCachePort := mock_outbound_port.NewMockLockoutCachePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientScope().Return(mockClientScopeDatabasePort).AnyTimes()
+		mockClientScopeDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientScope{{ClientID: 1, Scope: "clients:read"}}, nil).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Token().Return(mockTokenCachePort).AnyTimes()
+		mockCachePort.EXPECT().Nonce().Return(mockNonceCachePort).AnyTimes()
+		mockCachePort.EXPECT().RateLimit().Return(mockRateLimitCachePort).AnyTimes()
+		mockCachePort.EXPECT().Lockout().Return(mockLockoutCachePort).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mockClientMessagePort).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
+		// Lockout is covered on its own below; elsewhere it stays out of the way
+		os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+		defer os.Unsetenv("AUTH_LOCKOUT_THRESHOLD")
+
 		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
 		adapter := gin_inbound_adapter.NewAdapter(dom)
 
@@ -96,6 +126,119 @@ func TestMiddlewareAdapter(t *testing.T) {
 			})
 		})
 
+		Convey("Metrics", func() {
+			router := gin.New()
+			router.Use(adapter.Middleware().Metrics())
+			router.GET("/metrics", gin.WrapH(metrics.Handler()))
+			router.GET("/test/:id", func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test/42", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldContainSubstring, `http_requests_total{method="GET",route="/test/:id",status="200"}`)
+			So(w.Body.String(), ShouldContainSubstring, "host_cpu_cores")
+		})
+
+		Convey("Tracing", func() {
+			router := gin.New()
+			router.Use(adapter.Middleware().Tracing())
+			router.GET("/test", func(c *gin.Context) {
+				trxID, _ := activity.GetTransactionID(c.Request.Context())
+				c.String(http.StatusOK, trxID)
+			})
+
+			Convey("Generates a transaction ID", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldNotBeEmpty)
+				So(w.Header().Get("X-Transaction-ID"), ShouldEqual, w.Body.String())
+			})
+
+			Convey("Reuses the caller transaction ID", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("X-Transaction-ID", "5f0c6a43-3c4e-4a8e-9a53-0d3b8f1c2e71")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Body.String(), ShouldEqual, "5f0c6a43-3c4e-4a8e-9a53-0d3b8f1c2e71")
+			})
+
+			Convey("Ignores a malformed transaction ID", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("X-Transaction-ID", "not-a-uuid")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Body.String(), ShouldNotEqual, "not-a-uuid")
+			})
+		})
+
+		Convey("InternalAuth with named keys", func() {
+			os.Setenv("INTERNAL_KEYS", `[
+				{"name":"deployer","key_hashes":["`+internalkey.Hash("deployer-key")+`"],"routes":["/internal/client-upsert"]},
+				{"name":"support","key_hashes":["`+internalkey.Hash("support-key")+`"],"routes":["/internal/client-find","/internal/client-r*"]},
+				{"name":"auditor","key_hashes":["`+internalkey.Hash("auditor-key")+`"],"routes":["GET /internal/v1/clients/:id"]}
+			]`)
+			defer os.Unsetenv("INTERNAL_KEYS")
+
+			var principal model.Principal
+			router := gin.New()
+			internal := router.Group("/internal", adapter.Middleware().InternalAuth())
+			handler := func(c *gin.Context) {
+				principal = c.MustGet("principal").(model.Principal)
+				c.String(http.StatusOK, "OK")
+			}
+			internal.POST("/client-upsert", handler)
+			internal.POST("/client-find", handler)
+			internal.POST("/client-revoke", handler)
+			internal.GET("/v1/clients/:id", handler)
+			internal.DELETE("/v1/clients/:id", handler)
+
+			requestMethod := func(method, path, key string) int {
+				req := httptest.NewRequest(method, path, nil)
+				req.Header.Set("Authorization", "Bearer "+key)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w.Code
+			}
+			request := func(path, key string) int {
+				return requestMethod(http.MethodPost, path, key)
+			}
+
+			Convey("Granted route identifies the credential", func() {
+				So(request("/internal/client-upsert", "deployer-key"), ShouldEqual, http.StatusOK)
+				So(principal.Source, ShouldEqual, model.PrincipalSourceInternal)
+				So(principal.Name, ShouldEqual, "deployer")
+			})
+
+			Convey("Route patterns", func() {
+				So(request("/internal/client-revoke", "support-key"), ShouldEqual, http.StatusOK)
+				So(principal.Name, ShouldEqual, "support")
+			})
+
+			Convey("Route outside the grant", func() {
+				So(request("/internal/client-find", "deployer-key"), ShouldEqual, http.StatusForbidden)
+				So(request("/internal/client-upsert", "support-key"), ShouldEqual, http.StatusForbidden)
+			})
+
+			Convey("Routes granted for one method", func() {
+				So(requestMethod(http.MethodGet, "/internal/v1/clients/1", "auditor-key"), ShouldEqual, http.StatusOK)
+				So(requestMethod(http.MethodDelete, "/internal/v1/clients/1", "auditor-key"), ShouldEqual, http.StatusForbidden)
+			})
+
+			Convey("Unknown key", func() {
+				So(request("/internal/client-find", "other-key"), ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
 		Convey("ClientAuth", func() {
 			router := gin.New()
 			router.Use(adapter.Middleware().ClientAuth())
@@ -119,13 +262,13 @@ func TestMiddlewareAdapter(t *testing.T) {
 				defer os.Unsetenv("AUTH_DRIVER")
 
 				// 1. Check Cache (Miss)
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				// 2. Check DB (Exists)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-				// 3. Fetch from DB for Caching
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.Client{{}}, nil).Times(1)
-				// 4. Set in Cache
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				// 2. Find candidates by key prefix (Hash matches)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ClientInput: model.ClientInput{BearerKeyHash: apikey.Hash("valid-client-key")}},
+				}, nil).Times(1)
+				// 3. Set in Cache
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer valid-client-key")
@@ -134,14 +277,53 @@ func TestMiddlewareAdapter(t *testing.T) {
 				So(w.Code, ShouldEqual, http.StatusOK)
 			})
 
+			Convey("Principal is exposed to handlers and the activity context", func() {
+				os.Setenv("AUTH_DRIVER", "database")
+				defer os.Unsetenv("AUTH_DRIVER")
+
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{ID: 7, ClientInput: model.ClientInput{
+					Name:          "billing",
+					BearerKeyHash: apikey.Hash("valid-client-key"),
+					Scopes:        []string{"clients:read"},
+				}}, nil).Times(1)
+
+				var (
+					fromGin     model.Principal
+					fromContext model.Principal
+					clientID    string
+				)
+				router.GET("/whoami", func(c *gin.Context) {
+					fromGin = c.MustGet("principal").(model.Principal)
+					ctx := activity.NewContextFrom(c.Request.Context(), "http_whoami")
+					fromContext, _ = model.PrincipalFromContext(ctx)
+					clientID, _ = activity.GetClientID(ctx)
+					c.String(http.StatusOK, "OK")
+				})
+
+				req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(fromGin.ClientID, ShouldEqual, 7)
+				So(fromGin.Name, ShouldEqual, "billing")
+				So(fromGin.Source, ShouldEqual, model.PrincipalSourceClient)
+				So(fromContext, ShouldResemble, fromGin)
+				So(clientID, ShouldEqual, "7")
+			})
+
 			Convey("Client does not exist", func() {
 				os.Setenv("AUTH_DRIVER", "database")
 				defer os.Unsetenv("AUTH_DRIVER")
 
 				// 1. Check Cache (Miss)
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				// 2. Check DB (Not Exists)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				// 2. Find candidates by key prefix (None)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				// 3. Find superseded keys in their grace period (None)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				// 4. Remember the unknown key
+				mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), apikey.Hash("nonexistent-key"), gomock.Any()).Return(nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer nonexistent-key")
@@ -155,15 +337,450 @@ func TestMiddlewareAdapter(t *testing.T) {
 				defer os.Unsetenv("AUTH_DRIVER")
 
 				// 1. Check Cache (Miss)
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				// 2. Check DB (Error)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, redis.Nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				// 2. Find candidates by key prefix (Error)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, redis.Nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer test-key")
 				w := httptest.NewRecorder()
 				router.ServeHTTP(w, req)
-				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+
+		Convey("Lockout", func() {
+			os.Setenv("AUTH_LOCKOUT_THRESHOLD", "3")
+			os.Setenv("AUTH_DRIVER", "database")
+			defer os.Unsetenv("AUTH_DRIVER")
+			os.Setenv("INTERNAL_KEY", "valid-key")
+			defer os.Unsetenv("INTERNAL_KEY")
+
+			router := gin.New()
+			router.GET("/client", adapter.Middleware().ClientAuth(), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+			router.GET("/internal", adapter.Middleware().InternalAuth(), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+
+			request := func(path, key string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set("Authorization", "Bearer "+key)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			Convey("Locked out clients never reach the client lookup", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(90*time.Second, nil).Times(1)
+
+				w := request("/client", "guessed-key")
+				So(w.Code, ShouldEqual, http.StatusTooManyRequests)
+				So(w.Header().Get("Retry-After"), ShouldEqual, "90")
+			})
+
+			Convey("Unknown client keys count as failures", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:192.0.2.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				So(request("/client", "guessed-key").Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Valid client keys reset the failures", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{ID: 1, ClientInput: model.ClientInput{
+					BearerKeyHash: apikey.Hash("valid-client-key"),
+				}}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().ResetFailures(gomock.Any(), "client:192.0.2.1").Return(nil).Times(1)
+
+				So(request("/client", "valid-client-key").Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Wrong internal keys lock the caller out at the threshold", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "internal:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "internal:192.0.2.1", gomock.Any()).Return(int64(3), nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddStrike(gomock.Any(), "internal:192.0.2.1", gomock.Any()).Return(int64(1), nil).Times(1)
+				mockLockoutCachePort.EXPECT().Lock(gomock.Any(), "internal:192.0.2.1", time.Minute).Return(nil).Times(1)
+
+				So(request("/internal", "wrong-key").Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Locked out internal callers are rejected even with the right key", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "internal:192.0.2.1").Return(time.Minute, nil).Times(1)
+
+				So(request("/internal", "valid-key").Code, ShouldEqual, http.StatusTooManyRequests)
+			})
+
+			newEngine := func() *gin.Engine {
+				engine, err := gin_inbound_adapter.NewEngine()
+				So(err, ShouldBeNil)
+				engine.GET("/client", adapter.Middleware().ClientAuth(), func(c *gin.Context) {
+					c.String(http.StatusOK, "OK")
+				})
+				return engine
+			}
+			forwarded := func(engine *gin.Engine, forwardedFor string) int {
+				req := httptest.NewRequest(http.MethodGet, "/client", nil)
+				req.Header.Set("Authorization", "Bearer guessed-key")
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w := httptest.NewRecorder()
+				engine.ServeHTTP(w, req)
+				return w.Code
+			}
+
+			Convey("Spoofed X-Forwarded-For does not reset the counter", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(time.Duration(0), nil).Times(2)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, nil).Times(2)
+				gomock.InOrder(
+					mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:192.0.2.1", gomock.Any()).Return(int64(1), nil),
+					mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:192.0.2.1", gomock.Any()).Return(int64(2), nil),
+				)
+
+				engine := newEngine()
+				So(forwarded(engine, "203.0.113.1"), ShouldEqual, http.StatusUnauthorized)
+				So(forwarded(engine, "203.0.113.2"), ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("X-Forwarded-For from a trusted proxy is the client IP", func() {
+				os.Setenv("SERVER_TRUSTED_PROXIES", "192.0.2.0/24")
+				defer os.Unsetenv("SERVER_TRUSTED_PROXIES")
+
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:203.0.113.1").Return(time.Duration(0), nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:203.0.113.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				engine := newEngine()
+				So(forwarded(engine, "203.0.113.1"), ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("ClientAuth with introspection", func() {
+			os.Setenv("AUTH_DRIVER", "introspection")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			var principal model.Principal
+			router := gin.New()
+			router.Use(adapter.Middleware().ClientAuth())
+			router.GET("/test", func(c *gin.Context) {
+				principal = c.MustGet("principal").(model.Principal)
+				c.String(http.StatusOK, "OK")
+			})
+
+			Convey("Active token", func() {
+				mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{
+					Active: true,
+					Principal: model.Principal{
+						Subject: "service-account",
+						Source:  model.PrincipalSourceIntrospection,
+						Scopes:  []string{"clients:read"},
+					},
+					ExpiresAt: time.Now().Add(time.Minute),
+				}, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer opaque-token")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(principal.Subject, ShouldEqual, "service-account")
+				So(principal.Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
+			Convey("Inactive token", func() {
+				mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{
+					ExpiresAt: time.Now().Add(time.Minute),
+				}, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer revoked-token")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("ClientAuth with mtls", func() {
+			os.Setenv("AUTH_DRIVER", "mtls")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			var principal model.Principal
+			router := gin.New()
+			router.Use(adapter.Middleware().ClientAuth())
+			router.GET("/test", func(c *gin.Context) {
+				principal = c.MustGet("principal").(model.Principal)
+				c.String(http.StatusOK, "OK")
+			})
+
+			verified := func(commonName string) *tls.ConnectionState {
+				return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+					{Subject: pkix.Name{CommonName: commonName}},
+				}}}
+			}
+
+			Convey("Without a client certificate", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Certificate mapped to a client", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ID: 3, ClientInput: model.ClientInput{Name: "billing", CertificateSubject: "billing"}},
+				}, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.TLS = verified("billing")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(principal.ClientID, ShouldEqual, 3)
+				So(principal.Source, ShouldEqual, model.PrincipalSourceCertificate)
+			})
+
+			Convey("Certificate without a client", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.TLS = verified("unknown")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("SignatureAuth", func() {
+			os.Setenv("SECRET_ENCRYPTION_KEY", "test-encryption-key")
+			defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+
+			sealed, _ := secretbox.Seal("signing-secret")
+			signer := model.Client{ID: 5, SealedSigningSecret: sealed, ClientInput: model.ClientInput{Name: "billing"}}
+
+			var (
+				principal model.Principal
+				received  string
+			)
+			router := gin.New()
+			router.POST("/signed", adapter.Middleware().SignatureAuth(), func(c *gin.Context) {
+				principal = c.MustGet("principal").(model.Principal)
+				body, _ := io.ReadAll(c.Request.Body)
+				received = string(body)
+				c.String(http.StatusOK, "OK")
+			})
+
+			newRequest := func(secret string) *http.Request {
+				body := `{"name":"billing"}`
+				timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+				req := httptest.NewRequest(http.MethodPost, "/signed?dry_run=1", strings.NewReader(body))
+				req.Header.Set(signature.ClientIDHeader, "5")
+				req.Header.Set(signature.TimestampHeader, timestamp)
+				req.Header.Set(signature.NonceHeader, "nonce-1")
+				req.Header.Set(signature.SignatureHeader, signature.Sign(secret, http.MethodPost, "/signed?dry_run=1", timestamp, "nonce-1", []byte(body)))
+				return req
+			}
+
+			Convey("Valid signature", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), "5:nonce-1", gomock.Any()).Return(true, nil).Times(1)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("signing-secret"))
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(principal.ClientID, ShouldEqual, 5)
+				So(principal.Source, ShouldEqual, model.PrincipalSourceSignature)
+				So(received, ShouldEqual, `{"name":"billing"}`)
+			})
+
+			Convey("Wrong secret", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("other-secret"))
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Replayed request", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("signing-secret"))
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Selected through AUTH_DRIVER", func() {
+				os.Setenv("AUTH_DRIVER", "hmac")
+				defer os.Unsetenv("AUTH_DRIVER")
+
+				authed := gin.New()
+				authed.Use(adapter.Middleware().ClientAuth())
+				authed.POST("/signed", func(c *gin.Context) {
+					c.String(http.StatusOK, "OK")
+				})
+
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+
+				w := httptest.NewRecorder()
+				authed.ServeHTTP(w, newRequest("signing-secret"))
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Oversized body", func() {
+				os.Setenv("REQUEST_SIGNATURE_MAX_BODY_BYTES", "8")
+				defer os.Unsetenv("REQUEST_SIGNATURE_MAX_BODY_BYTES")
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("signing-secret"))
+				So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+			})
+
+			Convey("Wrong secret counts as a lockout failure", func() {
+				os.Setenv("AUTH_LOCKOUT_THRESHOLD", "3")
+				defer os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:192.0.2.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("other-secret"))
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Locked out signers are rejected before verification", func() {
+				os.Setenv("AUTH_LOCKOUT_THRESHOLD", "3")
+				defer os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(time.Minute, nil).Times(1)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, newRequest("signing-secret"))
+				So(w.Code, ShouldEqual, http.StatusTooManyRequests)
+			})
+		})
+
+		Convey("RequireScopes", func() {
+			os.Setenv("AUTH_DRIVER", "database")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			cached := model.Client{ID: 1, ClientInput: model.ClientInput{
+				BearerKeyHash: apikey.Hash("valid-client-key"),
+				Scopes:        []string{"clients:read"},
+			}}
+
+			router := gin.New()
+			router.GET("/open", adapter.Middleware().RequireScopes(), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+			authed := router.Group("/", adapter.Middleware().ClientAuth())
+			authed.GET("/read", adapter.Middleware().RequireScopes("clients:read"), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+			authed.GET("/write", adapter.Middleware().RequireScopes("clients:read", "clients:write"), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+
+			Convey("Without a principal", func() {
+				req := httptest.NewRequest(http.MethodGet, "/open", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Granted scope", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(cached, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/read", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Missing scope", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(cached, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/write", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusForbidden)
+			})
+		})
+
+		Convey("RateLimit", func() {
+			router := gin.New()
+			router.GET("/test", adapter.Middleware().RateLimit(), func(c *gin.Context) {
+				c.String(http.StatusOK, "OK")
+			})
+
+			Convey("Keys anonymous callers by IP with the default limit", func() {
+				mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1", 600, time.Minute).
+					Return(model.RateLimitResult{Allowed: true, Limit: 600, Remaining: 599, ResetAfter: 1500 * time.Millisecond}, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get("RateLimit-Limit"), ShouldEqual, "600")
+				So(w.Header().Get("RateLimit-Remaining"), ShouldEqual, "599")
+				So(w.Header().Get("RateLimit-Reset"), ShouldEqual, "2")
+			})
+
+			Convey("Keys clients by principal with their own limit", func() {
+				os.Setenv("AUTH_DRIVER", "database")
+				defer os.Unsetenv("AUTH_DRIVER")
+
+				cached := model.Client{ID: 7, ClientInput: model.ClientInput{
+					Name:          "limited",
+					BearerKeyHash: apikey.Hash("valid-client-key"),
+					RateLimit:     5,
+				}}
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(cached, nil).Times(1)
+				mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), gomock.Any(), 5, time.Minute).
+					DoAndReturn(func(_ interface{}, key string, limit int, _ time.Duration) (model.RateLimitResult, error) {
+						So(key, ShouldStartWith, model.PrincipalSourceClient+":")
+						return model.RateLimitResult{Allowed: true, Limit: limit, Remaining: limit - 1, ResetAfter: time.Minute}, nil
+					}).Times(1)
+
+				authed := gin.New()
+				authed.GET("/test", adapter.Middleware().ClientAuth(), adapter.Middleware().RateLimit(), func(c *gin.Context) {
+					c.String(http.StatusOK, "OK")
+				})
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				w := httptest.NewRecorder()
+				authed.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get("RateLimit-Limit"), ShouldEqual, "5")
+			})
+
+			Convey("Rejects callers over the limit", func() {
+				mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(model.RateLimitResult{Allowed: false, Limit: 600, RetryAfter: 250 * time.Millisecond, ResetAfter: time.Minute}, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusTooManyRequests)
+				So(w.Header().Get("Retry-After"), ShouldEqual, "1")
+				So(w.Header().Get("RateLimit-Remaining"), ShouldEqual, "0")
+			})
+
+			Convey("Sends no headers when limiting is disabled", func() {
+				os.Setenv("RATE_LIMIT_DEFAULT", "0")
+				defer os.Unsetenv("RATE_LIMIT_DEFAULT")
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Header().Get("RateLimit-Limit"), ShouldBeEmpty)
 			})
 		})
 	})
@@ -0,0 +1,204 @@
+package gin_inbound_adapter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-template/internal/model"
+	"go-template/utils/apikey"
+	"go-template/utils/openapi"
+)
+
+const (
+	securityInternal = "internalKey"
+	securityClient   = "clientAuth"
+)
+
+// operation documents one route of InitRoute; TestOpenAPI fails when a route has none
+type operation struct {
+	method   string
+	path     string
+	tag      string
+	summary  string
+	security string
+	// query is a struct whose form tags are the query parameters
+	query any
+	// body is the JSON request body
+	body any
+	// data is the type of model.Response.Data on success; nil leaves it out
+	data any
+	// raw replaces the model.Response envelope of the success response
+	raw any
+	// produces is the content type of a success response that is not JSON
+	produces string
+	// status is the success status, 200 when zero
+	status int
+	errors []int
+}
+
+var internalErrors = []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
+func withErrors(codes ...int) []int {
+	return append(append([]int{}, internalErrors...), codes...)
+}
+
+var operations = []operation{
+	{method: http.MethodGet, path: "/metrics", tag: "operations", summary: "Prometheus metrics", produces: "text/plain"},
+	{method: http.MethodGet, path: "/healthz", tag: "operations", summary: "Liveness probe", data: model.Health{}},
+	{method: http.MethodGet, path: "/readyz", tag: "operations", summary: "Readiness probe, checking every dependency", data: model.Health{},
+		errors: []int{http.StatusServiceUnavailable}},
+	{method: http.MethodGet, path: "/openapi.json", tag: "operations", summary: "This OpenAPI document", raw: map[string]any{}},
+	{method: http.MethodGet, path: "/docs/*filepath", tag: "operations", summary: "Swagger UI", produces: "text/html"},
+
+	{method: http.MethodPost, path: "/internal/client-upsert", tag: "clients (legacy)", summary: "Create or update clients by bearer key",
+		security: securityInternal, body: []model.ClientInput{}, data: []model.Client{}, errors: withErrors(http.StatusConflict)},
+	{method: http.MethodPost, path: "/internal/client-find", tag: "clients (legacy)", summary: "Find clients, one page at a time",
+		security: securityInternal, body: model.ClientFilter{}, data: []model.Client{}, errors: withErrors()},
+	{method: http.MethodDelete, path: "/internal/client-delete", tag: "clients (legacy)", summary: "Delete the matching clients",
+		security: securityInternal, body: model.ClientFilter{}, errors: withErrors()},
+	{method: http.MethodPost, path: "/internal/client-rotate", tag: "clients", summary: "Rotate the bearer key of a client",
+		security: securityInternal, body: model.ClientRotateInput{}, data: model.Client{}, errors: withErrors(http.StatusNotFound)},
+	{method: http.MethodPost, path: "/internal/client-revoke", tag: "clients", summary: "Revoke the keys of the matching clients",
+		security: securityInternal, body: model.ClientFilter{}, errors: withErrors()},
+	{method: http.MethodPost, path: "/internal/client-signing-secret", tag: "clients", summary: "Issue a new request signing secret",
+		security: securityInternal, body: model.ClientSigningSecretInput{}, data: model.Client{}, errors: withErrors(http.StatusNotFound)},
+
+	{method: http.MethodGet, path: "/internal/v1/clients", tag: "clients", summary: "List clients, one page at a time",
+		security: securityInternal, query: model.ClientFilter{}, data: []model.Client{}, errors: withErrors()},
+	{method: http.MethodPost, path: "/internal/v1/clients", tag: "clients", summary: "Create a client",
+		security: securityInternal, body: model.ClientInput{}, data: model.Client{}, status: http.StatusCreated, errors: withErrors(http.StatusConflict)},
+	{method: http.MethodGet, path: "/internal/v1/clients/:id", tag: "clients", summary: "Get a client",
+		security: securityInternal, data: model.Client{}, errors: withErrors(http.StatusNotFound)},
+	{method: http.MethodPut, path: "/internal/v1/clients/:id", tag: "clients", summary: "Replace the editable fields of a client",
+		security: securityInternal, body: model.ClientInput{}, data: model.Client{}, errors: withErrors(http.StatusNotFound, http.StatusConflict)},
+	{method: http.MethodPatch, path: "/internal/v1/clients/:id", tag: "clients", summary: "Change the given fields of a client",
+		security: securityInternal, body: model.ClientPatch{}, data: model.Client{}, errors: withErrors(http.StatusNotFound, http.StatusConflict)},
+	{method: http.MethodDelete, path: "/internal/v1/clients/:id", tag: "clients", summary: "Delete a client",
+		security: securityInternal, status: http.StatusNoContent, errors: withErrors(http.StatusNotFound)},
+
+	{method: http.MethodGet, path: "/v1/ping", tag: "v1", summary: "Check client authentication",
+		security: securityClient, raw: struct {
+			Message string `json:"message"`
+		}{}, errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests}},
+}
+
+// openAPIDocument is built once from operations and the model types
+var openAPIDocument = sync.OnceValue(func() openapi.Document {
+	generator := openapi.NewGenerator()
+	generator.Rules["bearer_key"] = func(s *openapi.Schema) {
+		minLength, maxLength := apikey.MinLength, apikey.MaxLength
+		s.MinLength, s.MaxLength = &minLength, &maxLength
+		s.Description = fmt.Sprintf("Printable ASCII without whitespace, using at least %d different characters. Generated when left out", apikey.MinDistinct)
+	}
+
+	document := openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "go-template",
+			Version:     "1.0.0",
+			Description: "Errors use the same envelope, with a machine readable code and, for validation errors, field details.",
+		},
+		Paths: make(map[string]*openapi.PathItem),
+		Components: openapi.Components{
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				securityInternal: {Type: "http", Scheme: "bearer", Description: "INTERNAL_KEY, or a named key of INTERNAL_KEYS granted the route"},
+				securityClient: {Type: "http", Scheme: "bearer", Description: "A client bearer key, or a token of the configured AUTH_DRIVER. " +
+					"Clients can also use a certificate or signed requests, see design-docs/authorization.md"},
+			},
+		},
+	}
+	envelope := generator.Schema(model.Response{})
+
+	for _, op := range operations {
+		path := openAPIPath(op.path)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			document.Paths[path] = item
+		}
+		(*item)[strings.ToLower(op.method)] = op.document(generator, envelope)
+	}
+
+	document.Components.Schemas = generator.Schemas
+	return document
+})
+
+func (op operation) document(generator *openapi.Generator, envelope *openapi.Schema) *openapi.Operation {
+	doc := &openapi.Operation{
+		Tags:        []string{op.tag},
+		Summary:     op.summary,
+		OperationID: operationID(op.method, op.path),
+		Responses:   make(map[string]*openapi.Response),
+	}
+	if op.security != "" {
+		doc.Security = []map[string][]string{{op.security: {}}}
+	}
+
+	for _, segment := range strings.Split(op.path, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			schema := &openapi.Schema{Type: "string"}
+			if segment == ":id" {
+				minimum := 0.0
+				schema = &openapi.Schema{Type: "integer", ExclusiveMinimum: &minimum}
+			}
+			doc.Parameters = append(doc.Parameters, &openapi.Parameter{Name: segment[1:], In: "path", Required: true, Schema: schema})
+		}
+	}
+	if op.query != nil {
+		doc.Parameters = append(doc.Parameters, generator.Parameters(op.query)...)
+	}
+	if op.body != nil {
+		doc.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSON(generator.Schema(op.body))}
+	}
+
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &openapi.Response{Description: http.StatusText(status)}
+	switch {
+	case status == http.StatusNoContent:
+	case op.produces != "":
+		success.Content = map[string]*openapi.MediaType{op.produces: {Schema: &openapi.Schema{Type: "string"}}}
+	case op.raw != nil:
+		success.Content = openapi.JSON(generator.Schema(op.raw))
+	case op.data != nil:
+		success.Content = openapi.JSON(&openapi.Schema{AllOf: []*openapi.Schema{
+			envelope,
+			{Type: "object", Properties: map[string]*openapi.Schema{"data": generator.Schema(op.data)}},
+		}})
+	default:
+		success.Content = openapi.JSON(envelope)
+	}
+	doc.Responses[strconv.Itoa(status)] = success
+
+	for _, code := range op.errors {
+		doc.Responses[strconv.Itoa(code)] = &openapi.Response{Description: http.StatusText(code), Content: openapi.JSON(envelope)}
+	}
+	return doc
+}
+
+// openAPIPath turns gin path parameters, :id and *filepath, into OpenAPI ones
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a stable id such as getInternalV1ClientsId
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
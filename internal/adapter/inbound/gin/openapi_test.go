@@ -0,0 +1,115 @@
+package gin_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	gin_inbound_adapter "go-template/internal/adapter/inbound/gin"
+	"go-template/internal/domain"
+	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apikey"
+	"go-template/utils/openapi"
+)
+
+var pathParameter = regexp.MustCompile(`[:*](\w+)`)
+
+func TestOpenAPI(t *testing.T) {
+	Convey("Test OpenAPI", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		dom := domain.NewDomain(
+			mock_outbound_port.NewMockDatabasePort(mockCtrl),
+			mock_outbound_port.NewMockMessagePort(mockCtrl),
+			mock_outbound_port.NewMockCachePort(mockCtrl),
+			mock_outbound_port.NewMockWorkflowPort(mockCtrl),
+		)
+
+		gin.SetMode(gin.TestMode)
+		app := gin.New()
+		gin_inbound_adapter.InitRoute(context.Background(), app, gin_inbound_adapter.NewAdapter(dom))
+
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		So(w.Code, ShouldEqual, http.StatusOK)
+
+		var document openapi.Document
+		So(json.Unmarshal(w.Body.Bytes(), &document), ShouldBeNil)
+		So(document.OpenAPI, ShouldEqual, "3.1.0")
+
+		var documented []string
+		for path, item := range document.Paths {
+			for method := range *item {
+				documented = append(documented, strings.ToUpper(method)+" "+path)
+			}
+		}
+		var registered []string
+		for _, route := range app.Routes() {
+			registered = append(registered, route.Method+" "+pathParameter.ReplaceAllString(route.Path, "{$1}"))
+		}
+
+		Convey("Every route of InitRoute is documented", func() {
+			for _, route := range registered {
+				So(documented, ShouldContain, route)
+			}
+		})
+
+		Convey("Every documented route is registered", func() {
+			for _, route := range documented {
+				So(registered, ShouldContain, route)
+			}
+		})
+
+		Convey("Models are described from their tags", func() {
+			input := document.Components.Schemas["ClientInput"]
+			So(input.Required, ShouldResemble, []string{"name"})
+			So(*input.Properties["name"].MaxLength, ShouldEqual, 100)
+			So(*input.Properties["bearer_key"].MinLength, ShouldEqual, apikey.MinLength)
+
+			client := document.Components.Schemas["Client"]
+			So(client.Properties, ShouldContainKey, "name")
+			// The key hash and sealed secret are tagged json:"-" and never appear,
+			// neither under that tag nor under their column or field names
+			for _, hidden := range []string{"-", "bearer_key_hash", "BearerKeyHash", "SealedSigningSecret"} {
+				So(client.Properties, ShouldNotContainKey, hidden)
+				So(input.Properties, ShouldNotContainKey, hidden)
+			}
+		})
+
+		Convey("Query parameters come from form tags", func() {
+			operation := (*document.Paths["/internal/v1/clients"])["get"]
+
+			var names []string
+			for _, parameter := range operation.Parameters {
+				names = append(names, parameter.Name)
+			}
+			So(names, ShouldContain, "search")
+			So(names, ShouldContain, "cursor")
+			So(names, ShouldNotContain, "bearer_keys")
+		})
+
+		Convey("Swagger UI loads the document", func() {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldContainSubstring, "swagger-initializer.js")
+
+			w = httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/swagger-initializer.js", nil))
+			So(w.Body.String(), ShouldContainSubstring, `"/openapi.json"`)
+
+			w = httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/swagger-ui-bundle.js", nil))
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
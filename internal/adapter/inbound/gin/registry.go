@@ -26,3 +26,11 @@ func (s *adapter) Middleware() inbound_port.MiddlewareHttpPort {
 func (s *adapter) Ping() inbound_port.PingHttpPort {
 	return NewPingAdapter(s.domain)
 }
+
+func (s *adapter) Health() inbound_port.HealthHttpPort {
+	return NewHealthAdapter(s.domain)
+}
+
+func (s *adapter) Docs() inbound_port.DocsHttpPort {
+	return NewDocsAdapter()
+}
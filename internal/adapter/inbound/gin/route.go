@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	inbound_port "go-template/internal/port/inbound"
+	"go-template/utils/metrics"
 )
 
 func InitRoute(
@@ -13,6 +14,19 @@ func InitRoute(
 	app *gin.Engine,
 	port inbound_port.HttpPort,
 ) {
+	app.Use(port.Middleware().Metrics())
+	app.Use(port.Middleware().Tracing())
+	app.Use(port.Middleware().ErrorHandler())
+	app.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Health routes are unauthenticated so orchestrators can probe them
+	app.GET("/healthz", port.Health().Liveness)
+	app.GET("/readyz", port.Health().Readiness)
+
+	// API description; every route below needs an entry in openapi.go
+	app.GET("/openapi.json", port.Docs().OpenAPI)
+	app.GET("/docs/*filepath", port.Docs().SwaggerUI)
+
 	// Internal routes with internal auth middleware
 	internal := app.Group("/internal")
 	internal.Use(port.Middleware().InternalAuth())
@@ -20,12 +34,29 @@ func InitRoute(
 		internal.POST("/client-upsert", port.Client().Upsert)
 		internal.POST("/client-find", port.Client().Find)
 		internal.DELETE("/client-delete", port.Client().Delete)
+		internal.POST("/client-rotate", port.Client().Rotate)
+		internal.POST("/client-revoke", port.Client().Revoke)
+		internal.POST("/client-signing-secret", port.Client().SigningSecret)
+	}
+
+	// Client resource; the RPC style routes above stay for existing callers
+	clients := internal.Group("/v1/clients")
+	{
+		clients.GET("", port.Client().List)
+		clients.POST("", port.Client().Create)
+		clients.GET("/:id", port.Client().Get)
+		clients.PUT("/:id", port.Client().Update)
+		clients.PATCH("/:id", port.Client().Patch)
+		clients.DELETE("/:id", port.Client().Remove)
 	}
 
 	// V1 routes with client auth middleware
 	v1 := app.Group("/v1")
 	v1.Use(port.Middleware().ClientAuth())
+	v1.Use(port.Middleware().RateLimit())
 	{
+		// Ping only checks authentication, so any client may call it. Routes
+		// that read or change data mount RequireScopes with their scopes
 		v1.GET("/ping", port.Ping().GetResource)
 	}
 }
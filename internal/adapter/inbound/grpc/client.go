@@ -0,0 +1,161 @@
+package grpc_inbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	inbound_port "go-template/internal/port/inbound"
+	clientv1 "go-template/proto/client/v1"
+	"go-template/utils/activity"
+)
+
+type clientAdapter struct {
+	clientv1.UnimplementedClientServiceServer
+	domain domain.Domain
+}
+
+func NewClientAdapter(
+	domain domain.Domain,
+) inbound_port.ClientGrpcPort {
+	return &clientAdapter{
+		domain: domain,
+	}
+}
+
+func (h *clientAdapter) Upsert(ctx context.Context, request *clientv1.UpsertRequest) (*clientv1.UpsertResponse, error) {
+	ctx = activity.NewContextFrom(ctx, "grpc_client_upsert")
+	payload := make([]model.ClientInput, len(request.GetClients()))
+	for i, input := range request.GetClients() {
+		payload[i] = clientInputFromProto(input)
+	}
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	results, err := h.domain.Client().Upsert(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientv1.UpsertResponse{
+		Clients: clientsToProto(results),
+	}, nil
+}
+
+func (h *clientAdapter) Find(ctx context.Context, request *clientv1.FindRequest) (*clientv1.FindResponse, error) {
+	ctx = activity.NewContextFrom(ctx, "grpc_client_find_by_filter")
+	payload := clientFilterFromProto(request.GetFilter())
+	payload.Limit = int(request.GetLimit())
+	payload.Sort = request.GetSort()
+	payload.Cursor = request.GetCursor()
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	results, page, err := h.domain.Client().FindByFilter(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientv1.FindResponse{
+		Clients:    clientsToProto(results),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}, nil
+}
+
+func (h *clientAdapter) Delete(ctx context.Context, request *clientv1.DeleteRequest) (*clientv1.DeleteResponse, error) {
+	ctx = activity.NewContextFrom(ctx, "grpc_client_delete_by_filter")
+	payload := clientFilterFromProto(request.GetFilter())
+
+	ctx = activity.WithPayload(ctx, payload)
+
+	err := h.domain.Client().DeleteByFilter(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientv1.DeleteResponse{}, nil
+}
+
+func (h *clientAdapter) Exists(ctx context.Context, request *clientv1.ExistsRequest) (*clientv1.ExistsResponse, error) {
+	// The bearer key is not recorded as payload, it would end up in the logs
+	ctx = activity.NewContextFrom(ctx, "grpc_client_is_exists")
+
+	exists, err := h.domain.Client().IsExists(ctx, request.GetBearerKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientv1.ExistsResponse{
+		Exists: exists,
+	}, nil
+}
+
+func clientInputFromProto(input *clientv1.ClientInput) model.ClientInput {
+	result := model.ClientInput{
+		Name:               input.GetName(),
+		BearerKey:          input.GetBearerKey(),
+		ExpiresAt:          timeFromProto(input.GetExpiresAt()),
+		CertificateSubject: input.GetCertificateSubject(),
+		RateLimit:          int(input.GetRateLimit()),
+	}
+	// Only a set scopes field replaces the stored scopes
+	if input.GetScopes() != nil {
+		result.Scopes = append([]string{}, input.GetScopes().GetValues()...)
+	}
+	return result
+}
+
+func clientFilterFromProto(filter *clientv1.ClientFilter) model.ClientFilter {
+	result := model.ClientFilter{
+		Names:               filter.GetNames(),
+		KeyPrefixes:         filter.GetKeyPrefixes(),
+		BearerKeys:          filter.GetBearerKeys(),
+		CertificateSubjects: filter.GetCertificateSubjects(),
+		Search:              filter.GetSearch(),
+	}
+	for _, id := range filter.GetIds() {
+		result.IDs = append(result.IDs, int(id))
+	}
+	return result
+}
+
+func clientsToProto(clients []model.Client) []*clientv1.Client {
+	results := make([]*clientv1.Client, len(clients))
+	for i, client := range clients {
+		results[i] = &clientv1.Client{
+			Id:                 int64(client.ID),
+			Name:               client.Name,
+			BearerKey:          client.BearerKey,
+			KeyPrefix:          client.KeyPrefix,
+			KeyVersion:         int32(client.KeyVersion),
+			ExpiresAt:          timeToProto(client.ExpiresAt),
+			RevokedAt:          timeToProto(client.RevokedAt),
+			LastUsedAt:         timeToProto(client.LastUsedAt),
+			CertificateSubject: client.CertificateSubject,
+			RateLimit:          int32(client.RateLimit),
+			Scopes:             client.Scopes,
+			CreatedAt:          timestamppb.New(client.CreatedAt),
+			UpdatedAt:          timestamppb.New(client.UpdatedAt),
+		}
+	}
+	return results
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
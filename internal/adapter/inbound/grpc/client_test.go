@@ -0,0 +1,257 @@
+package grpc_inbound_adapter_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	grpc_inbound_adapter "go-template/internal/adapter/inbound/grpc"
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	inbound_port "go-template/internal/port/inbound"
+	outbound_port "go-template/internal/port/outbound"
+	clientv1 "go-template/proto/client/v1"
+	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apikey"
+)
+
+// serve runs the server built by InitRoute on an in-memory listener
+func serve(t *testing.T, port inbound_port.GrpcPort) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc_inbound_adapter.InitRoute(context.Background(), port)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func withKey(ctx context.Context, key string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+key)
+}
+
+// errorReason returns the machine readable code carried by an ErrorInfo detail
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestClientAdapter(t *testing.T) {
+	Convey("Test Client gRPC Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientScopeDatabasePort := mock_outbound_port.NewMockClientScopeDatabasePort(mockCtrl)
+		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
+		mockLockoutCachePort := mock_outbound_port.NewMockLockoutCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientScope().Return(mockClientScopeDatabasePort).AnyTimes()
+		mockClientScopeDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientScope{{ClientID: 1, Scope: "clients:read"}}, nil).AnyTimes()
+		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Lockout().Return(mockLockoutCachePort).AnyTimes()
+
+		// Lockout is covered by the HTTP middleware tests; here it stays out of the way
+		os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+		defer os.Unsetenv("AUTH_LOCKOUT_THRESHOLD")
+		os.Setenv("INTERNAL_KEY", "valid-key")
+		defer os.Unsetenv("INTERNAL_KEY")
+
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		inTransaction := func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+			return txFunc(mockDatabasePort)
+		}
+		client := clientv1.NewClientServiceClient(serve(t, grpc_inbound_adapter.NewAdapter(dom)))
+		ctx := withKey(context.Background(), "valid-key")
+
+		expiresAt := time.Now().Add(time.Hour).UTC()
+		outputs := []model.Client{
+			{
+				ID: 1,
+				ClientInput: model.ClientInput{
+					Name:      "Test Client",
+					KeyPrefix: "abcdefgh",
+					ExpiresAt: &expiresAt,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+			},
+			{
+				ID:          2,
+				ClientInput: model.ClientInput{Name: "Other Client"},
+			},
+		}
+
+		Convey("Upsert", func() {
+			Convey("Success", func() {
+				var stored []model.ClientInput
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inputs []model.ClientInput) error {
+					stored = inputs
+					return nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs[:1], nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				resp, err := client.Upsert(ctx, &clientv1.UpsertRequest{Clients: []*clientv1.ClientInput{
+					{Name: "Test Client", Scopes: &clientv1.Scopes{Values: []string{"clients:read"}}},
+				}})
+
+				So(err, ShouldBeNil)
+				So(resp.GetClients(), ShouldHaveLength, 1)
+				So(resp.GetClients()[0].GetId(), ShouldEqual, 1)
+				So(resp.GetClients()[0].GetName(), ShouldEqual, "Test Client")
+				So(resp.GetClients()[0].GetExpiresAt().AsTime(), ShouldEqual, expiresAt)
+				So(resp.GetClients()[0].GetRevokedAt(), ShouldBeNil)
+				So(stored[0].Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
+			Convey("Unset scopes leave the stored ones alone", func() {
+				var stored []model.ClientInput
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inputs []model.ClientInput) error {
+					stored = inputs
+					return nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs[:1], nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				_, err := client.Upsert(ctx, &clientv1.UpsertRequest{Clients: []*clientv1.ClientInput{{Name: "Test Client"}}})
+
+				So(err, ShouldBeNil)
+				So(stored[0].Scopes, ShouldBeNil)
+			})
+
+			Convey("Invalid input", func() {
+				_, err := client.Upsert(ctx, &clientv1.UpsertRequest{Clients: []*clientv1.ClientInput{{Name: ""}}})
+
+				So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+				So(errorReason(err), ShouldEqual, "validation_error")
+
+				var violations []*errdetails.BadRequest_FieldViolation
+				for _, detail := range status.Convert(err).Details() {
+					if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+						violations = badRequest.GetFieldViolations()
+					}
+				}
+				So(violations, ShouldHaveLength, 1)
+				So(violations[0].GetField(), ShouldEqual, "[0].name")
+				So(violations[0].GetReason(), ShouldEqual, "required")
+			})
+
+			Convey("Unavailable dependency", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+				_, err := client.Upsert(ctx, &clientv1.UpsertRequest{Clients: []*clientv1.ClientInput{{Name: "Test Client"}}})
+
+				So(status.Code(err), ShouldEqual, codes.Unavailable)
+				So(status.Convert(err).Message(), ShouldEqual, "service unavailable")
+				So(errorReason(err), ShouldEqual, "unavailable")
+			})
+		})
+
+		Convey("Find", func() {
+			Convey("Success", func() {
+				var found model.ClientFilter
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					found = filter
+					return outputs, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(2), nil).Times(1)
+
+				resp, err := client.Find(ctx, &clientv1.FindRequest{Filter: &clientv1.ClientFilter{Ids: []int64{1, 2}}, Limit: 1})
+
+				So(err, ShouldBeNil)
+				So(resp.GetClients(), ShouldHaveLength, 1)
+				So(resp.GetTotal(), ShouldEqual, 2)
+				So(resp.GetNextCursor(), ShouldEqual, model.EncodeCursor(1))
+				So(found.IDs, ShouldResemble, []int{1, 2})
+				So(found.Limit, ShouldEqual, 2)
+			})
+
+			Convey("Invalid sort", func() {
+				_, err := client.Find(ctx, &clientv1.FindRequest{Sort: "name"})
+
+				So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			})
+		})
+
+		Convey("Delete", func() {
+			Convey("Success", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs[:1], nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				_, err := client.Delete(ctx, &clientv1.DeleteRequest{Filter: &clientv1.ClientFilter{Names: []string{"Test Client"}}})
+
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Empty filter", func() {
+				_, err := client.Delete(ctx, &clientv1.DeleteRequest{})
+
+				So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+				So(status.Convert(err).Message(), ShouldEqual, "filter is empty")
+			})
+		})
+
+		Convey("Exists", func() {
+			Convey("Known key", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ID: 1, ClientInput: model.ClientInput{BearerKeyHash: apikey.Hash("valid-client-key")}},
+				}, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				resp, err := client.Exists(ctx, &clientv1.ExistsRequest{BearerKey: "valid-client-key"})
+
+				So(err, ShouldBeNil)
+				So(resp.GetExists(), ShouldBeTrue)
+			})
+
+			Convey("Empty key", func() {
+				_, err := client.Exists(ctx, &clientv1.ExistsRequest{})
+
+				So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			})
+		})
+	})
+}
@@ -0,0 +1,54 @@
+package grpc_inbound_adapter
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	inbound_port "go-template/internal/port/inbound"
+	clientv1 "go-template/proto/client/v1"
+	pingv1 "go-template/proto/ping/v1"
+	"go-template/utils/activity"
+)
+
+// LivenessService is checked like /healthz; the server ("") and every service
+// it registers are checked like /readyz
+const LivenessService = "liveness"
+
+type healthAdapter struct {
+	grpc_health_v1.UnimplementedHealthServer
+	domain domain.Domain
+}
+
+func NewHealthAdapter(
+	domain domain.Domain,
+) inbound_port.HealthGrpcPort {
+	return &healthAdapter{
+		domain: domain,
+	}
+}
+
+// Check answers the standard gRPC health check from the health domain. Watch
+// is left unimplemented, which clients read as health checking being disabled
+func (h *healthAdapter) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	var result model.Health
+	switch request.GetService() {
+	case LivenessService:
+		ctx = activity.NewContextFrom(ctx, "grpc_health_liveness")
+		result = h.domain.Health().Liveness(ctx)
+	case "", clientv1.ClientService_ServiceDesc.ServiceName, pingv1.PingService_ServiceDesc.ServiceName:
+		ctx = activity.NewContextFrom(ctx, "grpc_health_readiness")
+		result = h.domain.Health().Readiness(ctx)
+	default:
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	if !result.IsUp() {
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
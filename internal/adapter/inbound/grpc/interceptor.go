@@ -0,0 +1,289 @@
+package grpc_inbound_adapter
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	inbound_port "go-template/internal/port/inbound"
+	"go-template/utils/activity"
+	"go-template/utils/apperror"
+	"go-template/utils/log"
+	"go-template/utils/mtls"
+	"go-template/utils/ratelimit"
+	"go-template/utils/tracing"
+)
+
+// Metadata keys are the lower case forms of the HTTP headers
+const (
+	transactionIDMetadata = "x-transaction-id"
+	authorizationMetadata = "authorization"
+	bearerPrefix          = "Bearer "
+
+	rateLimitLimitMetadata     = "ratelimit-limit"
+	rateLimitRemainingMetadata = "ratelimit-remaining"
+	rateLimitResetMetadata     = "ratelimit-reset"
+	retryAfterMetadata         = "retry-after"
+
+	// auditMethod fills model.InternalAudit.Method, which holds the HTTP method for HTTP requests
+	auditMethod = "GRPC"
+)
+
+type interceptorAdapter struct {
+	domain domain.Domain
+}
+
+func NewInterceptorAdapter(
+	domain domain.Domain,
+) inbound_port.InterceptorGrpcPort {
+	return &interceptorAdapter{
+		domain: domain,
+	}
+}
+
+// InternalAuth identifies the caller by one of the named internal keys and
+// only lets it call the methods that key was granted. Routes of a key match
+// full method names, e.g. /client.v1.ClientService/Find
+func (h *interceptorAdapter) InternalAuth() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = activity.NewContextFrom(ctx, "grpc_internal_auth")
+		principal, credential, err := h.domain.Auth().AuthenticateInternal(ctx, model.Credentials{
+			IP:          peerIP(ctx),
+			BearerToken: bearerToken(ctx),
+		})
+		if err != nil {
+			return nil, errorStatus(ctx, err)
+		}
+
+		ctx = model.ContextWithPrincipal(ctx, principal)
+		if !credential.Allows(auditMethod, info.FullMethod) {
+			log.WithContext(activity.WithResult(ctx, model.InternalAudit{
+				Credential: credential.Name,
+				Method:     auditMethod,
+				Route:      info.FullMethod,
+				Status:     int(codes.PermissionDenied),
+			})).Warn("internal request denied")
+			return nil, errorStatus(ctx, apperror.NewForbidden("Forbidden"))
+		}
+
+		resp, err := handler(ctx, req)
+
+		log.WithContext(activity.WithResult(ctx, model.InternalAudit{
+			Credential: credential.Name,
+			Method:     auditMethod,
+			Route:      info.FullMethod,
+			Status:     int(status.Code(err)),
+		})).Info("internal request")
+		return resp, err
+	}
+}
+
+// ClientAuth identifies the caller with the configured AUTH_DRIVER, like the
+// HTTP middleware. Signed requests are HTTP only, a signature covers the
+// method, URI and body of an HTTP request
+func (h *interceptorAdapter) ClientAuth() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = activity.NewContextFrom(ctx, "grpc_client_auth")
+		principal, err := h.domain.Auth().AuthenticateClient(ctx, model.Credentials{
+			IP:                    peerIP(ctx),
+			BearerToken:           bearerToken(ctx),
+			CertificateIdentities: certificateIdentities(ctx),
+		})
+		if err != nil {
+			return nil, errorStatus(ctx, err)
+		}
+
+		return handler(model.ContextWithPrincipal(ctx, principal), req)
+	}
+}
+
+// RateLimit limits each caller, identified by the principal set by ClientAuth
+// or by its IP when there is none, and reports the limit in ratelimit-* headers
+func (h *interceptorAdapter) RateLimit() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limitCtx := activity.NewContextFrom(ctx, "grpc_rate_limit")
+
+		key, limit := "ip:"+peerIP(ctx), 0
+		if principal, ok := model.PrincipalFromContext(ctx); ok {
+			key, limit = principal.Source+":"+principal.Subject, principal.RateLimit
+		}
+
+		result := h.domain.RateLimit().Allow(limitCtx, key, limit)
+		header := metadata.MD{}
+		if result.Limit > 0 {
+			header.Set(rateLimitLimitMetadata, strconv.Itoa(result.Limit))
+			header.Set(rateLimitRemainingMetadata, strconv.Itoa(result.Remaining))
+			header.Set(rateLimitResetMetadata, strconv.Itoa(ratelimit.Seconds(result.ResetAfter)))
+		}
+
+		if !result.Allowed {
+			header.Set(retryAfterMetadata, strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+			_ = grpc.SetHeader(ctx, header)
+			return nil, status.Error(codes.ResourceExhausted, "Too Many Requests")
+		}
+
+		_ = grpc.SetHeader(ctx, header)
+		return handler(ctx, req)
+	}
+}
+
+// ErrorHandler turns the error a method returned into a gRPC status, so every
+// method reports failures with the same code mapping. It runs innermost, so
+// the other interceptors see the final status
+func (h *interceptorAdapter) ErrorHandler() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+
+		return nil, errorStatus(activity.NewContextFrom(ctx, "grpc_error"), err)
+	}
+}
+
+// errorStatus maps err to its gRPC code. The machine readable code travels as
+// the reason of an ErrorInfo detail and field errors as a BadRequest detail.
+// Server side failures are logged in full, callers only get a generic message
+func errorStatus(ctx context.Context, err error) error {
+	code := apperror.GRPCCode(err)
+	if code == codes.Internal || code == codes.Unavailable {
+		log.WithContext(ctx).Error("grpc request error", err)
+	}
+	if retryAfter := apperror.RetryAfter(err); retryAfter > 0 {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, strconv.Itoa(ratelimit.Seconds(retryAfter))))
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: apperror.Code(err)}}
+	if fields := apperror.Details(err); len(fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+		for i, field := range fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+				Reason:      field.Rule,
+			}
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	st := status.New(code, apperror.Message(err))
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// Tracing starts the server span for the call and seeds the activity
+// transaction ID, reusing the caller's x-transaction-id when it is a valid UUID
+func (h *interceptorAdapter) Tracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = tracing.Extract(ctx, metadataCarrier(md))
+
+		trxID := firstMetadata(md, transactionIDMetadata)
+		if _, err := uuid.Parse(trxID); err != nil {
+			trxID = uuid.NewString()
+		}
+		ctx = activity.WithTransactionID(ctx, trxID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(transactionIDMetadata, trxID))
+
+		ctx, span := tracing.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+				attribute.String("transaction_id", trxID),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if code == codes.Internal || code == codes.Unavailable || code == codes.Unknown {
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		return resp, err
+	}
+}
+
+// metadataCarrier lets the trace propagators read incoming metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	return firstMetadata(metadata.MD(c), key)
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := firstMetadata(md, authorizationMetadata)
+	if len(authorization) > len(bearerPrefix) && strings.HasPrefix(authorization, bearerPrefix) {
+		return authorization[len(bearerPrefix):]
+	}
+	return ""
+}
+
+// certificateIdentities reads the client certificate verified during the TLS handshake
+func certificateIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return mtls.Identities(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// peerIP is the address lockouts and IP rate limits are keyed by
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,250 @@
+package grpc_inbound_adapter_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	grpc_inbound_adapter "go-template/internal/adapter/inbound/grpc"
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	clientv1 "go-template/proto/client/v1"
+	pingv1 "go-template/proto/ping/v1"
+	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apikey"
+	"go-template/utils/internalkey"
+)
+
+func TestInterceptorAdapter(t *testing.T) {
+	Convey("Test Interceptor gRPC Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockClientScopeDatabasePort := mock_outbound_port.NewMockClientScopeDatabasePort(mockCtrl)
+		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
+		mockLockoutCachePort := mock_outbound_port.NewMockLockoutCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientScope().Return(mockClientScopeDatabasePort).AnyTimes()
+		mockClientScopeDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Lockout().Return(mockLockoutCachePort).AnyTimes()
+		// Last used timestamps of authenticated clients are flushed in the background
+		mockClientDatabasePort.EXPECT().UpdateLastUsed(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+		os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+		defer os.Unsetenv("AUTH_LOCKOUT_THRESHOLD")
+		// Counted per domain in memory, so every case starts from a full allowance
+		os.Setenv("RATE_LIMIT_STORE", "memory")
+		defer os.Unsetenv("RATE_LIMIT_STORE")
+
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		conn := serve(t, grpc_inbound_adapter.NewAdapter(dom))
+		client := clientv1.NewClientServiceClient(conn)
+		ping := pingv1.NewPingServiceClient(conn)
+		ctx := context.Background()
+
+		Convey("InternalAuth", func() {
+			os.Setenv("INTERNAL_KEY", "valid-key")
+			defer os.Unsetenv("INTERNAL_KEY")
+
+			Convey("Missing authorization", func() {
+				_, err := client.Exists(ctx, &clientv1.ExistsRequest{BearerKey: "key"})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Unknown key", func() {
+				_, err := client.Exists(withKey(ctx, "invalid-key"), &clientv1.ExistsRequest{BearerKey: "key"})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Client keys are not internal keys", func() {
+				os.Setenv("AUTH_DRIVER", "database")
+				defer os.Unsetenv("AUTH_DRIVER")
+
+				_, err := client.Exists(withKey(ctx, "valid-client-key"), &clientv1.ExistsRequest{BearerKey: "key"})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Keys only call the methods they were granted", func() {
+				os.Setenv("INTERNAL_KEYS", `[{"name": "reader", "key_hashes": ["`+internalkey.Hash("reader-key")+`"], "routes": ["/client.v1.ClientService/Find"]}]`)
+				defer os.Unsetenv("INTERNAL_KEYS")
+
+				_, err := client.Exists(withKey(ctx, "reader-key"), &clientv1.ExistsRequest{BearerKey: "key"})
+				So(status.Code(err), ShouldEqual, codes.PermissionDenied)
+			})
+		})
+
+		Convey("ClientAuth", func() {
+			os.Setenv("AUTH_DRIVER", "database")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			Convey("Missing authorization", func() {
+				_, err := ping.Ping(ctx, &pingv1.PingRequest{})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Unknown key", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				_, err := ping.Ping(withKey(ctx, "invalid-client-key"), &pingv1.PingRequest{})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Known key", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ID: 1, ClientInput: model.ClientInput{BearerKeyHash: apikey.Hash("valid-client-key"), RateLimit: 5}},
+				}, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				var header metadata.MD
+				resp, err := ping.Ping(withKey(ctx, "valid-client-key"), &pingv1.PingRequest{}, grpc.Header(&header))
+				So(err, ShouldBeNil)
+				So(resp.GetMessage(), ShouldEqual, "pong")
+				So(header.Get("ratelimit-limit"), ShouldResemble, []string{"5"})
+				So(header.Get("ratelimit-remaining"), ShouldResemble, []string{"4"})
+			})
+
+			Convey("Locked out callers are told when to retry", func() {
+				os.Setenv("AUTH_LOCKOUT_THRESHOLD", "10")
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), gomock.Any()).Return(90*time.Second, nil).Times(1)
+
+				var header metadata.MD
+				_, err := ping.Ping(withKey(ctx, "guessed-key"), &pingv1.PingRequest{}, grpc.Header(&header))
+				So(status.Code(err), ShouldEqual, codes.ResourceExhausted)
+				So(header.Get("retry-after"), ShouldResemble, []string{"90"})
+			})
+
+			Convey("Signed requests are HTTP only", func() {
+				os.Setenv("AUTH_DRIVER", "hmac")
+
+				_, err := ping.Ping(ctx, &pingv1.PingRequest{})
+				So(status.Code(err), ShouldEqual, codes.Unauthenticated)
+			})
+
+			Convey("Dependency failures are not authentication failures", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+				_, err := ping.Ping(withKey(ctx, "valid-client-key"), &pingv1.PingRequest{})
+				So(status.Code(err), ShouldEqual, codes.Unavailable)
+			})
+		})
+
+		Convey("RateLimit", func() {
+			os.Setenv("AUTH_DRIVER", "database")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{
+				ID:          1,
+				ClientInput: model.ClientInput{BearerKeyHash: apikey.Hash("valid-client-key"), RateLimit: 1},
+			}, nil).Times(2)
+
+			_, err := ping.Ping(withKey(ctx, "valid-client-key"), &pingv1.PingRequest{})
+			So(err, ShouldBeNil)
+
+			var header metadata.MD
+			_, err = ping.Ping(withKey(ctx, "valid-client-key"), &pingv1.PingRequest{}, grpc.Header(&header))
+			So(status.Code(err), ShouldEqual, codes.ResourceExhausted)
+			So(header.Get("retry-after"), ShouldHaveLength, 1)
+		})
+
+		Convey("Tracing", func() {
+			os.Setenv("INTERNAL_KEY", "valid-key")
+			defer os.Unsetenv("INTERNAL_KEY")
+			trxID := uuid.NewString()
+
+			Convey("Reuses the caller's transaction ID", func() {
+				var header metadata.MD
+				_, _ = client.Exists(metadata.AppendToOutgoingContext(ctx, "x-transaction-id", trxID), &clientv1.ExistsRequest{}, grpc.Header(&header))
+				So(header.Get("x-transaction-id"), ShouldResemble, []string{trxID})
+			})
+
+			Convey("Replaces an invalid one", func() {
+				var header metadata.MD
+				_, _ = client.Exists(metadata.AppendToOutgoingContext(ctx, "x-transaction-id", "invalid"), &clientv1.ExistsRequest{}, grpc.Header(&header))
+				So(header.Get("x-transaction-id"), ShouldHaveLength, 1)
+				So(header.Get("x-transaction-id")[0], ShouldNotEqual, "invalid")
+			})
+		})
+
+		Convey("Health", func() {
+			health := grpc_health_v1.NewHealthClient(conn)
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+			mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+			mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+
+			Convey("Serving when every dependency is up", func() {
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+
+				resp, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+				So(err, ShouldBeNil)
+				So(resp.GetStatus(), ShouldEqual, grpc_health_v1.HealthCheckResponse_SERVING)
+
+				resp, err = health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "client.v1.ClientService"})
+				So(err, ShouldBeNil)
+				So(resp.GetStatus(), ShouldEqual, grpc_health_v1.HealthCheckResponse_SERVING)
+			})
+
+			Convey("Not serving when a dependency is down", func() {
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused")).AnyTimes()
+
+				resp, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+				So(err, ShouldBeNil)
+				So(resp.GetStatus(), ShouldEqual, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+				resp, err = health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: grpc_inbound_adapter.LivenessService})
+				So(err, ShouldBeNil)
+				So(resp.GetStatus(), ShouldEqual, grpc_health_v1.HealthCheckResponse_SERVING)
+			})
+
+			Convey("Unknown service", func() {
+				_, err := health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "unknown.v1.Service"})
+				So(status.Code(err), ShouldEqual, codes.NotFound)
+			})
+		})
+
+		Convey("Reflection lists every service", func() {
+			stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			So(err, ShouldBeNil)
+			So(stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+				MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+			}), ShouldBeNil)
+			resp, err := stream.Recv()
+			So(err, ShouldBeNil)
+
+			var services []string
+			for _, service := range resp.GetListServicesResponse().GetService() {
+				services = append(services, service.GetName())
+			}
+			So(services, ShouldContain, "client.v1.ClientService")
+			So(services, ShouldContain, "ping.v1.PingService")
+			So(services, ShouldContain, "grpc.health.v1.Health")
+		})
+	})
+}
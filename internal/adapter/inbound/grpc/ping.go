@@ -0,0 +1,28 @@
+package grpc_inbound_adapter
+
+import (
+	"context"
+
+	"go-template/internal/domain"
+	inbound_port "go-template/internal/port/inbound"
+	pingv1 "go-template/proto/ping/v1"
+)
+
+type pingAdapter struct {
+	pingv1.UnimplementedPingServiceServer
+	domain domain.Domain
+}
+
+func NewPingAdapter(
+	domain domain.Domain,
+) inbound_port.PingGrpcPort {
+	return &pingAdapter{
+		domain: domain,
+	}
+}
+
+func (h *pingAdapter) Ping(ctx context.Context, request *pingv1.PingRequest) (*pingv1.PingResponse, error) {
+	return &pingv1.PingResponse{
+		Message: "pong",
+	}, nil
+}
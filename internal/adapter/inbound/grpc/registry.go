@@ -0,0 +1,32 @@
+package grpc_inbound_adapter
+
+import (
+	"go-template/internal/domain"
+	inbound_port "go-template/internal/port/inbound"
+)
+
+type adapter struct {
+	domain domain.Domain
+}
+
+func NewAdapter(domain domain.Domain) inbound_port.GrpcPort {
+	return &adapter{
+		domain: domain,
+	}
+}
+
+func (s *adapter) Interceptor() inbound_port.InterceptorGrpcPort {
+	return NewInterceptorAdapter(s.domain)
+}
+
+func (s *adapter) Health() inbound_port.HealthGrpcPort {
+	return NewHealthAdapter(s.domain)
+}
+
+func (s *adapter) Ping() inbound_port.PingGrpcPort {
+	return NewPingAdapter(s.domain)
+}
+
+func (s *adapter) Client() inbound_port.ClientGrpcPort {
+	return NewClientAdapter(s.domain)
+}
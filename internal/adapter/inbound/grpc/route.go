@@ -0,0 +1,59 @@
+package grpc_inbound_adapter
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/selector"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	inbound_port "go-template/internal/port/inbound"
+	clientv1 "go-template/proto/client/v1"
+	pingv1 "go-template/proto/ping/v1"
+)
+
+// InitRoute builds the gRPC server. Interceptors are fixed when the server is
+// created, so unlike the other InitRoute functions this one returns the server
+func InitRoute(
+	ctx context.Context,
+	port inbound_port.GrpcPort,
+	options ...grpc.ServerOption,
+) *grpc.Server {
+	internal := forServices(clientv1.ClientService_ServiceDesc.ServiceName)
+	client := forServices(pingv1.PingService_ServiceDesc.ServiceName)
+
+	// Health checking and reflection are unauthenticated so orchestrators and tools can reach them
+	options = append(options, grpc.ChainUnaryInterceptor(
+		port.Interceptor().Tracing(),
+		selector.UnaryServerInterceptor(port.Interceptor().InternalAuth(), internal),
+		selector.UnaryServerInterceptor(port.Interceptor().ClientAuth(), client),
+		selector.UnaryServerInterceptor(port.Interceptor().RateLimit(), client),
+		port.Interceptor().ErrorHandler(),
+	))
+	server := grpc.NewServer(options...)
+
+	grpc_health_v1.RegisterHealthServer(server, port.Health())
+	reflection.Register(server)
+
+	// Internal services, with internal auth
+	clientv1.RegisterClientServiceServer(server, port.Client())
+
+	// Client services, with client auth and rate limiting
+	pingv1.RegisterPingServiceServer(server, port.Ping())
+
+	return server
+}
+
+// forServices matches the calls to any of the given fully qualified services
+func forServices(services ...string) selector.Matcher {
+	return selector.MatchFunc(func(ctx context.Context, call interceptors.CallMeta) bool {
+		for _, service := range services {
+			if call.Service == service {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"go-template/internal/model"
 	inbound_port "go-template/internal/port/inbound"
 	"go-template/utils/activity"
+	"go-template/utils/apperror"
 	"go-template/utils/log"
 )
 
@@ -23,9 +24,9 @@ func NewClientAdapter(
 	}
 }
 
-func (h *clientAdapter) Upsert(a any) bool {
+func (h *clientAdapter) Upsert(ctx context.Context, a any) bool {
 	msg := a.([]byte)
-	ctx := activity.NewContext("message_client_upsert")
+	ctx = activity.NewContextFrom(ctx, "message_client_upsert")
 	var payload []model.ClientInput
 	err := json.Unmarshal(msg, &payload)
 	if err != nil {
@@ -37,6 +38,8 @@ func (h *clientAdapter) Upsert(a any) bool {
 	results, err := h.domain.Client().Upsert(ctx, payload)
 	if err != nil {
 		log.WithContext(ctx).Error("client upsert error", err)
+		// Retry only when a dependency is down; anything else would fail again
+		return !apperror.Retryable(err)
 	}
 	ctx = context.WithValue(ctx, activity.Result, results)
 
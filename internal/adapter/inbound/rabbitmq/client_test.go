@@ -0,0 +1,62 @@
+package rabbitmq_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	rabbitmq_inbound_adapter "go-template/internal/adapter/inbound/rabbitmq"
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apperror"
+)
+
+func TestClientAdapter(t *testing.T) {
+	Convey("Test Client Message Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+
+		adapter := rabbitmq_inbound_adapter.NewAdapter(domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort))
+		inTransaction := func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+			return txFunc(mockDatabasePort)
+		}
+		ctx := context.Background()
+		msg, _ := json.Marshal([]model.ClientInput{{Name: "Test Client"}})
+
+		Convey("Malformed messages are discarded", func() {
+			So(adapter.Client().Upsert(ctx, []byte("invalid")), ShouldBeTrue)
+		})
+
+		Convey("Invalid input is discarded", func() {
+			So(adapter.Client().Upsert(ctx, []byte("[]")), ShouldBeTrue)
+		})
+
+		Convey("Conflicts are discarded", func() {
+			mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+			mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(apperror.NewConflict("conflict")).Times(1)
+
+			So(adapter.Client().Upsert(ctx, msg), ShouldBeTrue)
+		})
+
+		Convey("Unavailable dependencies are retried", func() {
+			mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+			mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+			So(adapter.Client().Upsert(ctx, msg), ShouldBeFalse)
+		})
+	})
+}
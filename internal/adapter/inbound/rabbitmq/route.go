@@ -26,8 +26,8 @@ func InitRoute(
 					rabbitmq.KindFanOut,
 					os.Getenv("UPSERT_CLIENT_MESSAGE_SUBSCRIBE"),
 					"",
-					func(msg []byte) bool {
-						return port.Client().Upsert(msg)
+					func(msgCtx context.Context, msg []byte) bool {
+						return port.Client().Upsert(msgCtx, msg)
 					},
 				)
 				if err != nil {
@@ -0,0 +1,39 @@
+package client_temporal_inbound_adapter
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/temporal"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	"go-template/utils/apperror"
+)
+
+// ClientActivity exposes the client domain to workflows. Methods keep the
+// domain names, so registered activity names do not change
+type ClientActivity struct {
+	domain domain.Domain
+}
+
+func NewClientActivity(
+	domain domain.Domain,
+) *ClientActivity {
+	return &ClientActivity{
+		domain: domain,
+	}
+}
+
+func (a *ClientActivity) Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error) {
+	results, err := a.domain.Client().Upsert(ctx, inputs)
+	return results, activityError(err)
+}
+
+// activityError stops Temporal from retrying errors that would fail again,
+// such as invalid input; unavailable dependencies keep the retry policy
+func activityError(err error) error {
+	if err == nil || apperror.Retryable(err) {
+		return err
+	}
+	return temporal.NewNonRetryableApplicationError(err.Error(), apperror.Code(err), err)
+}
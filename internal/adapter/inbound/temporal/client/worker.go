@@ -35,7 +35,7 @@ func (a *clientAdapter) Upsert() {
 	workflow := NewClientWorkflow(a.domain)
 
 	w.RegisterWorkflow(workflow.UpsertClientWorkflow)
-	w.RegisterActivity(a.domain.Client())
+	w.RegisterActivity(NewClientActivity(a.domain))
 
 	err = w.Run(worker.InterruptCh())
 	if err != nil {
@@ -14,14 +14,16 @@ type ClientWorkflow interface {
 }
 
 type clientWorkflow struct {
-	domain domain.Domain
+	domain   domain.Domain
+	activity *ClientActivity
 }
 
 func NewClientWorkflow(
 	domain domain.Domain,
 ) ClientWorkflow {
 	return &clientWorkflow{
-		domain: domain,
+		domain:   domain,
+		activity: NewClientActivity(domain),
 	}
 }
 
@@ -39,7 +41,7 @@ func (g *clientWorkflow) UpsertClientWorkflow(ctx workflow.Context, input model.
 	var results []model.Client
 	err := workflow.ExecuteActivity(
 		ctx,
-		g.domain.Client().Upsert,
+		g.activity.Upsert,
 		[]model.ClientInput{input},
 	).Get(ctx, &results)
 	if err != nil {
@@ -47,12 +49,13 @@ func (g *clientWorkflow) UpsertClientWorkflow(ctx workflow.Context, input model.
 		return "Failed to upsert client", err
 	}
 
-	var bearerKey string
+	// Never log the plaintext key, the prefix is enough to identify it
+	var keyPrefix string
 	if len(results) > 0 {
-		bearerKey = results[0].BearerKey
+		keyPrefix = results[0].KeyPrefix
 	}
 
-	successMessage := "Bearer key: " + bearerKey
+	successMessage := "Key prefix: " + keyPrefix
 	logger.Info(successMessage, "WorkflowID", workflowInfo.WorkflowExecution.ID)
 
 	return successMessage, nil
@@ -1,15 +1,24 @@
 package postgres_outbound_adapter
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"go-template/internal/model"
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/apperror"
+	"go-template/utils/metrics"
 )
 
 const tableClient = "clients"
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type clientAdapter struct {
 	db *gorm.DB
 }
@@ -23,44 +32,79 @@ func NewClientAdapter(
 }
 
 // Upsert inserts or updates client records
-func (adapter *clientAdapter) Upsert(datas []model.ClientInput) error {
+func (adapter *clientAdapter) Upsert(ctx context.Context, datas []model.ClientInput) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_upsert", start, err) }(time.Now())
+
 	// Build the data structures for GORM
 	clients := make([]map[string]interface{}, len(datas))
 	for i, data := range datas {
 		clients[i] = map[string]interface{}{
-			"name":       data.Name,
-			"bearer_key": data.BearerKey,
-			"created_at": data.CreatedAt,
-			"updated_at": data.UpdatedAt,
+			"name":                data.Name,
+			"key_prefix":          data.KeyPrefix,
+			"bearer_key_hash":     data.BearerKeyHash,
+			"expires_at":          data.ExpiresAt,
+			"certificate_subject": data.CertificateSubject,
+			"rate_limit":          data.RateLimit,
+			"created_at":          data.CreatedAt,
+			"updated_at":          data.UpdatedAt,
 		}
 	}
 
 	// Use GORM's Clauses for ON CONFLICT handling
-	return adapter.db.Table(tableClient).
+	err = adapter.db.WithContext(ctx).Table(tableClient).
 		Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "bearer_key"}},
-			DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+			Columns:   []clause.Column{{Name: "bearer_key_hash"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "expires_at", "certificate_subject", "rate_limit", "updated_at"}),
 		}).
 		Create(clients).Error
+	// The key hash is the upsert target, so any other unique index clashed
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.NewConflict("certificate_subject is already used by another client")
+	}
+	return err
 }
 
-// FindByFilter retrieves clients based on filter criteria
-func (adapter *clientAdapter) FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error) {
-	var clients []model.Client
+// Insert creates a client, reporting a taken key or certificate as a conflict
+func (adapter *clientAdapter) Insert(ctx context.Context, data model.ClientInput) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_insert", start, err) }(time.Now())
+
+	err = adapter.db.WithContext(ctx).Table(tableClient).
+		Create(map[string]interface{}{
+			"name":                data.Name,
+			"key_prefix":          data.KeyPrefix,
+			"bearer_key_hash":     data.BearerKeyHash,
+			"expires_at":          data.ExpiresAt,
+			"certificate_subject": data.CertificateSubject,
+			"rate_limit":          data.RateLimit,
+			"created_at":          data.CreatedAt,
+			"updated_at":          data.UpdatedAt,
+		}).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.NewConflict("bearer_key or certificate_subject is already used by another client")
+	}
+	return err
+}
 
-	query := adapter.db.Table(tableClient)
+// FindByFilter retrieves clients based on filter criteria
+func (adapter *clientAdapter) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) (clients []model.Client, err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_find_by_filter", start, err) }(time.Now())
 
-	// Apply filters
-	if len(filter.IDs) > 0 {
-		query = query.Where("id IN ?", filter.IDs)
-	}
+	query := applyClientFilter(adapter.db.WithContext(ctx).Table(tableClient), filter)
 
-	if len(filter.Names) > 0 {
-		query = query.Where("name IN ?", filter.Names)
+	// Keyset pagination on the primary key stays fast however deep the page
+	if filter.Sort == model.ClientSortIDDesc {
+		if filter.AfterID > 0 {
+			query = query.Where("id < ?", filter.AfterID)
+		}
+		query = query.Order("id DESC")
+	} else {
+		if filter.AfterID > 0 {
+			query = query.Where("id > ?", filter.AfterID)
+		}
+		query = query.Order("id")
 	}
-
-	if len(filter.BearerKeys) > 0 {
-		query = query.Where("bearer_key IN ?", filter.BearerKeys)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
 	}
 
 	// Add row locking if requested
@@ -69,7 +113,7 @@ func (adapter *clientAdapter) FindByFilter(filter model.ClientFilter, lock bool)
 	}
 
 	// Execute query
-	err := query.Find(&clients).Error
+	err = query.Find(&clients).Error
 	if err != nil {
 		return nil, err
 	}
@@ -77,11 +121,105 @@ func (adapter *clientAdapter) FindByFilter(filter model.ClientFilter, lock bool)
 	return clients, nil
 }
 
+// CountByFilter counts the clients matching filter criteria
+func (adapter *clientAdapter) CountByFilter(ctx context.Context, filter model.ClientFilter) (count int64, err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_count_by_filter", start, err) }(time.Now())
+
+	err = applyClientFilter(adapter.db.WithContext(ctx).Table(tableClient), filter).Count(&count).Error
+	return count, err
+}
+
 // DeleteByFilter deletes clients based on filter criteria
-func (adapter *clientAdapter) DeleteByFilter(filter model.ClientFilter) error {
-	query := adapter.db.Table(tableClient)
+func (adapter *clientAdapter) DeleteByFilter(ctx context.Context, filter model.ClientFilter) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_delete_by_filter", start, err) }(time.Now())
+
+	query := applyClientFilter(adapter.db.WithContext(ctx).Table(tableClient), filter)
+
+	// Execute delete
+	return query.Delete(&model.Client{}).Error
+}
+
+// Update replaces the editable fields of a client
+func (adapter *clientAdapter) Update(ctx context.Context, data model.Client) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_update", start, err) }(time.Now())
+
+	err = adapter.db.WithContext(ctx).Table(tableClient).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"name":                data.Name,
+			"expires_at":          data.ExpiresAt,
+			"certificate_subject": data.CertificateSubject,
+			"rate_limit":          data.RateLimit,
+			"updated_at":          data.UpdatedAt,
+		}).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.NewConflict("certificate_subject is already used by another client")
+	}
+	return err
+}
+
+// UpdateKey replaces the current bearer key of a client
+func (adapter *clientAdapter) UpdateKey(ctx context.Context, data model.Client) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_update_key", start, err) }(time.Now())
+
+	return adapter.db.WithContext(ctx).Table(tableClient).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"key_prefix":      data.KeyPrefix,
+			"bearer_key_hash": data.BearerKeyHash,
+			"key_version":     data.KeyVersion,
+			"updated_at":      data.UpdatedAt,
+		}).Error
+}
+
+// UpdateSigningSecret replaces the sealed signing secret of a client
+func (adapter *clientAdapter) UpdateSigningSecret(ctx context.Context, data model.Client) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_update_signing_secret", start, err) }(time.Now())
+
+	return adapter.db.WithContext(ctx).Table(tableClient).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"signing_secret": data.SealedSigningSecret,
+			"updated_at":     data.UpdatedAt,
+		}).Error
+}
 
-	// Apply filters
+// RevokeByFilter marks the matching clients as revoked, keeping the first revocation time
+func (adapter *clientAdapter) RevokeByFilter(ctx context.Context, filter model.ClientFilter, at time.Time) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_revoke_by_filter", start, err) }(time.Now())
+
+	query := applyClientFilter(adapter.db.WithContext(ctx).Table(tableClient), filter)
+
+	return query.Where("revoked_at IS NULL").
+		Updates(map[string]interface{}{
+			"revoked_at": at,
+			"updated_at": at,
+		}).Error
+}
+
+// UpdateLastUsed records last used timestamps for many clients in a single statement
+func (adapter *clientAdapter) UpdateLastUsed(ctx context.Context, usage map[int]time.Time) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_update_last_used", start, err) }(time.Now())
+
+	if len(usage) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(usage))
+	args := make([]interface{}, 0, len(usage)*2)
+	for id, at := range usage {
+		values = append(values, "(?::integer, ?::timestamp)")
+		args = append(args, id, at)
+	}
+
+	// Never move last_used_at backwards when batches from several instances overlap
+	return adapter.db.WithContext(ctx).Exec(`UPDATE `+tableClient+` SET last_used_at = v.last_used_at
+		FROM (VALUES `+strings.Join(values, ", ")+`) AS v(id, last_used_at)
+		WHERE `+tableClient+`.id = v.id
+		AND (`+tableClient+`.last_used_at IS NULL OR `+tableClient+`.last_used_at < v.last_used_at)`, args...).Error
+}
+
+func applyClientFilter(query *gorm.DB, filter model.ClientFilter) *gorm.DB {
 	if len(filter.IDs) > 0 {
 		query = query.Where("id IN ?", filter.IDs)
 	}
@@ -90,25 +228,22 @@ func (adapter *clientAdapter) DeleteByFilter(filter model.ClientFilter) error {
 		query = query.Where("name IN ?", filter.Names)
 	}
 
-	if len(filter.BearerKeys) > 0 {
-		query = query.Where("bearer_key IN ?", filter.BearerKeys)
+	if len(filter.KeyPrefixes) > 0 {
+		query = query.Where("key_prefix IN ?", filter.KeyPrefixes)
 	}
 
-	// Execute delete
-	return query.Delete(&model.Client{}).Error
-}
-
-// IsExists checks if a client exists by bearer key
-func (adapter *clientAdapter) IsExists(bearerKey string) (bool, error) {
-	var count int64
+	if len(filter.BearerKeyHashes) > 0 {
+		query = query.Where("bearer_key_hash IN ?", filter.BearerKeyHashes)
+	}
 
-	err := adapter.db.Table(tableClient).
-		Where("bearer_key = ?", bearerKey).
-		Count(&count).Error
+	if len(filter.CertificateSubjects) > 0 {
+		query = query.Where("certificate_subject IN ?", filter.CertificateSubjects)
+	}
 
-	if err != nil {
-		return false, err
+	if filter.Search != "" {
+		// Matches idx_clients_name_lower; wildcards in the search are taken literally
+		query = query.Where("lower(name) LIKE ?", likeEscaper.Replace(strings.ToLower(filter.Search))+"%")
 	}
 
-	return count > 0, nil
+	return query
 }
@@ -0,0 +1,69 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/metrics"
+)
+
+const tableClientKey = "client_keys"
+
+type clientKeyAdapter struct {
+	db *gorm.DB
+}
+
+func NewClientKeyAdapter(
+	db *gorm.DB,
+) outbound_port.ClientKeyDatabasePort {
+	return &clientKeyAdapter{
+		db: db,
+	}
+}
+
+// Insert records a superseded client key
+func (adapter *clientKeyAdapter) Insert(ctx context.Context, data model.ClientKey) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_key_insert", start, err) }(time.Now())
+
+	return adapter.db.WithContext(ctx).Table(tableClientKey).
+		Create(map[string]interface{}{
+			"client_id":       data.ClientID,
+			"version":         data.Version,
+			"key_prefix":      data.KeyPrefix,
+			"bearer_key_hash": data.BearerKeyHash,
+			"rotated_at":      data.RotatedAt,
+			"expires_at":      data.ExpiresAt,
+		}).Error
+}
+
+// FindByFilter retrieves client keys based on filter criteria
+func (adapter *clientKeyAdapter) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) (keys []model.ClientKey, err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_key_find_by_filter", start, err) }(time.Now())
+
+	query := adapter.db.WithContext(ctx).Table(tableClientKey)
+
+	// Apply filters
+	if len(filter.ClientIDs) > 0 {
+		query = query.Where("client_id IN ?", filter.ClientIDs)
+	}
+
+	if len(filter.KeyPrefixes) > 0 {
+		query = query.Where("key_prefix IN ?", filter.KeyPrefixes)
+	}
+
+	if !filter.ActiveAt.IsZero() {
+		query = query.Where("expires_at > ?", filter.ActiveAt)
+	}
+
+	// Execute query
+	err = query.Order("version DESC").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
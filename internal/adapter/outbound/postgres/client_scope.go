@@ -0,0 +1,71 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/metrics"
+)
+
+const tableClientScope = "client_scopes"
+
+type clientScopeAdapter struct {
+	db *gorm.DB
+}
+
+func NewClientScopeAdapter(
+	db *gorm.DB,
+) outbound_port.ClientScopeDatabasePort {
+	return &clientScopeAdapter{
+		db: db,
+	}
+}
+
+// FindByFilter retrieves client scopes based on filter criteria
+func (adapter *clientScopeAdapter) FindByFilter(ctx context.Context, filter model.ClientScopeFilter) (scopes []model.ClientScope, err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_scope_find_by_filter", start, err) }(time.Now())
+
+	query := adapter.db.WithContext(ctx).Table(tableClientScope)
+
+	// Apply filters
+	if len(filter.ClientIDs) > 0 {
+		query = query.Where("client_id IN ?", filter.ClientIDs)
+	}
+
+	// Execute query
+	err = query.Order("client_id, scope").Find(&scopes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return scopes, nil
+}
+
+// Replace swaps the whole scope set of a client
+func (adapter *clientScopeAdapter) Replace(ctx context.Context, clientID int, scopes []string) (err error) {
+	defer func(start time.Time) { metrics.ObserveDatabaseQuery("client_scope_replace", start, err) }(time.Now())
+
+	return adapter.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table(tableClientScope).Where("client_id = ?", clientID).Delete(&model.ClientScope{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(scopes) == 0 {
+			return nil
+		}
+
+		rows := make([]map[string]interface{}, len(scopes))
+		for i, scope := range scopes {
+			rows[i] = map[string]interface{}{
+				"client_id": clientID,
+				"scope":     scope,
+			}
+		}
+		return tx.Table(tableClientScope).Create(rows).Error
+	})
+}
@@ -11,6 +11,8 @@ import (
 	postgres_outbound_adapter "go-template/internal/adapter/outbound/postgres"
 	"go-template/internal/model"
 	"go-template/tests/helpers"
+	"go-template/utils/apikey"
+	"go-template/utils/apperror"
 )
 
 func TestClientAdapter(t *testing.T) {
@@ -42,15 +44,16 @@ func TestClientAdapter(t *testing.T) {
 
 		now := time.Now().Truncate(time.Microsecond)
 		input := model.ClientInput{
-			Name:      "Test Client",
-			BearerKey: "test-key-integration",
-			CreatedAt: now,
-			UpdatedAt: now,
+			Name:          "Test Client",
+			KeyPrefix:     apikey.Prefix("test-key-integration"),
+			BearerKeyHash: apikey.Hash("test-key-integration"),
+			CreatedAt:     now,
+			UpdatedAt:     now,
 		}
 
 		Convey("Upsert", func() {
 			Convey("Insert new record", func() {
-				err := adapter.Upsert([]model.ClientInput{input})
+				err := adapter.Upsert(ctx, []model.ClientInput{input})
 				So(err, ShouldBeNil)
 
 				var count int64
@@ -60,23 +63,24 @@ func TestClientAdapter(t *testing.T) {
 				var stored model.Client
 				pgContainer.DB.First(&stored)
 				So(stored.Name, ShouldEqual, input.Name)
-				So(stored.BearerKey, ShouldEqual, input.BearerKey)
+				So(stored.KeyPrefix, ShouldEqual, input.KeyPrefix)
+				So(stored.BearerKeyHash, ShouldEqual, input.BearerKeyHash)
 			})
 
-			Convey("Update existing record (Conflict on BearerKey)", func() {
+			Convey("Update existing record (Conflict on BearerKeyHash)", func() {
 				// First insert
-				adapter.Upsert([]model.ClientInput{input})
+				adapter.Upsert(ctx, []model.ClientInput{input})
 
 				// Update data
 				updatedInput := input
 				updatedInput.Name = "Updated Name"
 
-				// Same BearerKey -> Should Update
-				err := adapter.Upsert([]model.ClientInput{updatedInput})
+				// Same BearerKeyHash -> Should Update
+				err := adapter.Upsert(ctx, []model.ClientInput{updatedInput})
 				So(err, ShouldBeNil)
 
 				var stored model.Client
-				pgContainer.DB.First(&stored, "bearer_key = ?", input.BearerKey)
+				pgContainer.DB.First(&stored, "bearer_key_hash = ?", input.BearerKeyHash)
 				So(stored.Name, ShouldEqual, "Updated Name")
 
 				var count int64
@@ -85,17 +89,55 @@ func TestClientAdapter(t *testing.T) {
 			})
 		})
 
+		Convey("Insert", func() {
+			Convey("New record", func() {
+				err := adapter.Insert(ctx, input)
+				So(err, ShouldBeNil)
+
+				var stored model.Client
+				pgContainer.DB.First(&stored)
+				So(stored.Name, ShouldEqual, input.Name)
+			})
+
+			Convey("Taken key is a conflict", func() {
+				So(adapter.Insert(ctx, input), ShouldBeNil)
+
+				err := adapter.Insert(ctx, input)
+				So(apperror.Code(err), ShouldEqual, apperror.Conflict)
+			})
+		})
+
+		Convey("Update", func() {
+			adapter.Upsert(ctx, []model.ClientInput{input})
+			var stored model.Client
+			pgContainer.DB.First(&stored)
+
+			Convey("Editable fields are replaced", func() {
+				stored.Name = "Updated Name"
+				stored.RateLimit = 10
+
+				err := adapter.Update(ctx, stored)
+				So(err, ShouldBeNil)
+
+				var updated model.Client
+				pgContainer.DB.First(&updated, stored.ID)
+				So(updated.Name, ShouldEqual, "Updated Name")
+				So(updated.RateLimit, ShouldEqual, 10)
+				So(updated.BearerKeyHash, ShouldEqual, input.BearerKeyHash)
+			})
+		})
+
 		Convey("FindByFilter", func() {
 			// Seed data
-			adapter.Upsert([]model.ClientInput{input})
+			adapter.Upsert(ctx, []model.ClientInput{input})
 
 			// Get actual ID
 			var stored model.Client
-			pgContainer.DB.First(&stored, "bearer_key = ?", input.BearerKey)
+			pgContainer.DB.First(&stored, "bearer_key_hash = ?", input.BearerKeyHash)
 
 			Convey("Find by ID", func() {
 				filter := model.ClientFilter{IDs: []int{stored.ID}}
-				results, err := adapter.FindByFilter(filter, false)
+				results, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 1)
 				So(results[0].ID, ShouldEqual, stored.ID)
@@ -103,49 +145,137 @@ func TestClientAdapter(t *testing.T) {
 
 			Convey("Find by Name", func() {
 				filter := model.ClientFilter{Names: []string{input.Name}}
-				results, err := adapter.FindByFilter(filter, false)
+				results, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 1)
 				So(results[0].Name, ShouldEqual, input.Name)
 			})
 
 			Convey("With Lock", func() {
-				filter := model.ClientFilter{BearerKeys: []string{input.BearerKey}}
-				results, err := adapter.FindByFilter(filter, true)
+				filter := model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}
+				results, err := adapter.FindByFilter(ctx, filter, true)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 1)
 			})
 
 			Convey("Empty Result", func() {
 				filter := model.ClientFilter{Names: []string{"Non Existent"}}
-				results, err := adapter.FindByFilter(filter, false)
+				results, err := adapter.FindByFilter(ctx, filter, false)
+				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Find by KeyPrefix", func() {
+			adapter.Upsert(ctx, []model.ClientInput{input})
+
+			Convey("Match", func() {
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{KeyPrefixes: []string{input.KeyPrefix}}, false)
+				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 1)
+				So(results[0].BearerKeyHash, ShouldEqual, input.BearerKeyHash)
+			})
+
+			Convey("No Match", func() {
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{KeyPrefixes: []string{"missing"}}, false)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 0)
 			})
 		})
 
-		Convey("IsExists", func() {
-			adapter.Upsert([]model.ClientInput{input})
+		Convey("Paging and search", func() {
+			names := []string{"Billing", "billing-eu", "Reports", "bill_%"}
+			for _, name := range names {
+				client := input
+				client.Name = name
+				client.BearerKeyHash = apikey.Hash(name)
+				So(adapter.Upsert(ctx, []model.ClientInput{client}), ShouldBeNil)
+			}
 
-			Convey("Exists", func() {
-				exists, err := adapter.IsExists(input.BearerKey)
+			Convey("Pages follow the id", func() {
+				first, err := adapter.FindByFilter(ctx, model.ClientFilter{Limit: 2}, false)
 				So(err, ShouldBeNil)
-				So(exists, ShouldBeTrue)
+				So(first, ShouldHaveLength, 2)
+				So(first[0].ID, ShouldBeLessThan, first[1].ID)
+
+				second, err := adapter.FindByFilter(ctx, model.ClientFilter{Limit: 2, AfterID: first[1].ID}, false)
+				So(err, ShouldBeNil)
+				So(second, ShouldHaveLength, 2)
+				So(second[0].ID, ShouldBeGreaterThan, first[1].ID)
+
+				newest, err := adapter.FindByFilter(ctx, model.ClientFilter{Limit: 1, Sort: model.ClientSortIDDesc}, false)
+				So(err, ShouldBeNil)
+				So(newest[0].Name, ShouldEqual, "bill_%")
 			})
 
-			Convey("Not Exists", func() {
-				exists, err := adapter.IsExists("non-existent-key")
+			Convey("Search matches a name prefix ignoring case", func() {
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{Search: "BILLING"}, false)
 				So(err, ShouldBeNil)
-				So(exists, ShouldBeFalse)
+				So(results, ShouldHaveLength, 2)
+			})
+
+			Convey("Search takes wildcards literally", func() {
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{Search: "bill_%"}, false)
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+			})
+
+			Convey("Count ignores paging", func() {
+				count, err := adapter.CountByFilter(ctx, model.ClientFilter{Search: "bill", Limit: 1, AfterID: 1})
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 3)
+			})
+		})
+
+		Convey("RevokeByFilter", func() {
+			adapter.Upsert(ctx, []model.ClientInput{input})
+
+			revokedAt := now.Add(time.Minute)
+			err := adapter.RevokeByFilter(ctx, model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}, revokedAt)
+			So(err, ShouldBeNil)
+
+			Convey("Revoked client is marked", func() {
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}, false)
+				So(err, ShouldBeNil)
+				So(results[0].RevokedAt, ShouldNotBeNil)
+				So(results[0].IsActive(revokedAt.Add(time.Second)), ShouldBeFalse)
+			})
+
+			Convey("Second revocation keeps the first time", func() {
+				err := adapter.RevokeByFilter(ctx, model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}, revokedAt.Add(time.Hour))
+				So(err, ShouldBeNil)
+
+				results, _ := adapter.FindByFilter(ctx, model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}, false)
+				So(results[0].RevokedAt.Equal(revokedAt), ShouldBeTrue)
+			})
+		})
+
+		Convey("UpdateLastUsed", func() {
+			adapter.Upsert(ctx, []model.ClientInput{input})
+
+			var stored model.Client
+			pgContainer.DB.First(&stored, "bearer_key_hash = ?", input.BearerKeyHash)
+
+			usedAt := now.Add(time.Minute)
+			err := adapter.UpdateLastUsed(ctx, map[int]time.Time{stored.ID: usedAt})
+			So(err, ShouldBeNil)
+
+			Convey("Older timestamps do not move it backwards", func() {
+				err := adapter.UpdateLastUsed(ctx, map[int]time.Time{stored.ID: now})
+				So(err, ShouldBeNil)
+
+				results, _ := adapter.FindByFilter(ctx, model.ClientFilter{IDs: []int{stored.ID}}, false)
+				So(results[0].LastUsedAt, ShouldNotBeNil)
+				So(results[0].LastUsedAt.Equal(usedAt), ShouldBeTrue)
 			})
 		})
 
 		Convey("DeleteByFilter", func() {
-			adapter.Upsert([]model.ClientInput{input})
+			adapter.Upsert(ctx, []model.ClientInput{input})
 
-			Convey("Delete by BearerKey", func() {
-				filter := model.ClientFilter{BearerKeys: []string{input.BearerKey}}
-				err := adapter.DeleteByFilter(filter)
+			Convey("Delete by BearerKeyHash", func() {
+				filter := model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}
+				err := adapter.DeleteByFilter(ctx, filter)
 				So(err, ShouldBeNil)
 
 				var count int64
@@ -1,6 +1,8 @@
 package postgres_outbound_adapter
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 
 	outbound_port "go-template/internal/port/outbound"
@@ -17,11 +19,11 @@ func NewAdapter(db *gorm.DB) outbound_port.DatabasePort {
 }
 
 // DoInTransaction executes a function within a database transaction
-func (s *adapter) DoInTransaction(txFunc outbound_port.InTransaction) (out interface{}, err error) {
+func (s *adapter) DoInTransaction(ctx context.Context, txFunc outbound_port.InTransaction) (out interface{}, err error) {
 	var result interface{}
 	var txErr error
 
-	err = s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Create a new adapter with the transaction
 		txAdapter := &adapter{
 			db: tx,
@@ -44,3 +46,20 @@ func (s *adapter) DoInTransaction(txFunc outbound_port.InTransaction) (out inter
 func (s *adapter) Client() outbound_port.ClientDatabasePort {
 	return NewClientAdapter(s.db)
 }
+
+func (s *adapter) ClientKey() outbound_port.ClientKeyDatabasePort {
+	return NewClientKeyAdapter(s.db)
+}
+
+func (s *adapter) ClientScope() outbound_port.ClientScopeDatabasePort {
+	return NewClientScopeAdapter(s.db)
+}
+
+// Ping checks that the connection pool can reach the database
+func (s *adapter) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -1,7 +1,10 @@
 package rabbitmq_outbound_adapter
 
 import (
+	"context"
+
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/rabbitmq"
 )
 
 type adapter struct {
@@ -14,3 +17,7 @@ func NewAdapter() outbound_port.MessagePort {
 func (s *adapter) Client() outbound_port.ClientMessagePort {
 	return NewClientAdapter()
 }
+
+func (s *adapter) Ping(ctx context.Context) error {
+	return rabbitmq.Ping()
+}
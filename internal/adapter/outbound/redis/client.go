@@ -3,39 +3,93 @@ package redis_outbound_adapter
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	goredis "github.com/redis/go-redis/v9"
 
 	"go-template/internal/model"
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/metrics"
 	"go-template/utils/redis"
 )
 
+const (
+	cacheClient = "client"
+)
+
+// cachedClient keeps the key hash, which model.Client omits from JSON, so
+// cache hits can still be verified against the presented key
+type cachedClient struct {
+	model.Client
+	BearerKeyHash string `json:"bearer_key_hash"`
+}
+
 type clientAdapter struct{}
 
 func NewClientAdapter() outbound_port.ClientCachePort {
 	return &clientAdapter{}
 }
 
-func (adapter *clientAdapter) Set(data model.Client) error {
-	bytes, err := json.Marshal(data)
+func (adapter *clientAdapter) Set(ctx context.Context, data model.Client) error {
+	bytes, err := json.Marshal(cachedClient{Client: data, BearerKeyHash: data.BearerKeyHash})
+	if err != nil {
+		return err
+	}
+	return redis.Set(ctx, cacheKey(data.BearerKeyHash), string(bytes))
+}
+
+// SetUnknown stores an entry without a key hash, which never verifies, under
+// the same key as real clients so DeleteMany also clears it
+func (adapter *clientAdapter) SetUnknown(ctx context.Context, bearerKeyHash string, ttl time.Duration) error {
+	bytes, err := json.Marshal(cachedClient{})
 	if err != nil {
 		return err
 	}
-	return redis.Set(context.Background(), data.BearerKey, string(bytes))
+	return redis.SetWithTTL(ctx, cacheKey(bearerKeyHash), string(bytes), ttl)
 }
 
-func (adapter *clientAdapter) Get(bearerKey string) (model.Client, error) {
-	var client model.Client
-	result, err := redis.Get(context.Background(), bearerKey)
+func (adapter *clientAdapter) Get(ctx context.Context, bearerKeyHash string) (model.Client, error) {
+	var cached cachedClient
+	result, err := redis.Get(ctx, cacheKey(bearerKeyHash))
 	if err != nil {
+		if err == goredis.Nil {
+			metrics.ObserveCacheLookup(cacheClient, metrics.ResultMiss)
+		} else {
+			metrics.ObserveCacheLookup(cacheClient, metrics.ResultError)
+		}
 		return model.Client{}, err
 	}
+	metrics.ObserveCacheLookup(cacheClient, metrics.ResultHit)
 
-	err = json.Unmarshal([]byte(result), &client)
+	err = json.Unmarshal([]byte(result), &cached)
 	if err != nil {
 		return model.Client{}, err
 	}
 
+	client := cached.Client
+	client.BearerKeyHash = cached.BearerKeyHash
 	return client, nil
 }
+
+func (adapter *clientAdapter) Delete(ctx context.Context, bearerKeyHash string) error {
+	return adapter.DeleteMany(ctx, []string{bearerKeyHash})
+}
+
+// DeleteMany drops the entries from the Redis cache every instance reads,
+// so one delete invalidates them everywhere
+func (adapter *clientAdapter) DeleteMany(ctx context.Context, bearerKeyHashes []string) error {
+	return redis.Del(ctx, cacheKeys(bearerKeyHashes)...)
+}
+
+func cacheKey(bearerKeyHash string) string {
+	return cacheClient + ":" + bearerKeyHash
+}
+
+func cacheKeys(bearerKeyHashes []string) []string {
+	keys := make([]string, len(bearerKeyHashes))
+	for i, bearerKeyHash := range bearerKeyHashes {
+		keys[i] = cacheKey(bearerKeyHash)
+	}
+	return keys
+}
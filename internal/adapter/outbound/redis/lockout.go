@@ -0,0 +1,45 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/redis"
+)
+
+const cacheLockout = "lockout"
+
+type lockoutAdapter struct{}
+
+func NewLockoutAdapter() outbound_port.LockoutCachePort {
+	return &lockoutAdapter{}
+}
+
+func (adapter *lockoutAdapter) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return redis.IncrWithTTL(ctx, lockoutKey("failures", key), window)
+}
+
+func (adapter *lockoutAdapter) AddStrike(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return redis.IncrRefreshTTL(ctx, lockoutKey("strikes", key), ttl)
+}
+
+func (adapter *lockoutAdapter) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	err := redis.SetWithTTL(ctx, lockoutKey("locked", key), 1, ttl)
+	if err != nil {
+		return err
+	}
+	return redis.Del(ctx, lockoutKey("failures", key))
+}
+
+func (adapter *lockoutAdapter) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	return redis.TTL(ctx, lockoutKey("locked", key))
+}
+
+func (adapter *lockoutAdapter) ResetFailures(ctx context.Context, key string) error {
+	return redis.Del(ctx, lockoutKey("failures", key))
+}
+
+func lockoutKey(kind, key string) string {
+	return cacheLockout + ":" + kind + ":" + key
+}
@@ -0,0 +1,22 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/redis"
+)
+
+const cacheNonce = "nonce"
+
+type nonceAdapter struct{}
+
+func NewNonceAdapter() outbound_port.NonceCachePort {
+	return &nonceAdapter{}
+}
+
+// Claim relies on SET NX, so concurrent replays across instances see only one winner
+func (adapter *nonceAdapter) Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return redis.SetNX(ctx, cacheNonce+":"+nonce, 1, ttl)
+}
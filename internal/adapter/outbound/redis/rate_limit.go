@@ -0,0 +1,63 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/ratelimit"
+	"go-template/utils/redis"
+)
+
+const cacheRateLimit = "ratelimit"
+
+// slidingWindowScript reads both windows and counts the request only when the
+// weighted estimate stays under the limit, atomically across instances
+var slidingWindowScript = goredis.NewScript(`
+local previous = tonumber(redis.call('GET', KEYS[1]) or '0')
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * tonumber(ARGV[2]) + current + 1 > tonumber(ARGV[1]) then
+	return {previous, current, 0}
+end
+current = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return {previous, current, 1}
+`)
+
+type rateLimitAdapter struct{}
+
+func NewRateLimitAdapter() outbound_port.RateLimitCachePort {
+	return &rateLimitAdapter{}
+}
+
+func (adapter *rateLimitAdapter) Allow(ctx context.Context, key string, limit int, window time.Duration) (model.RateLimitResult, error) {
+	index, elapsed := ratelimit.Window(time.Now(), window)
+	// The hash tag keeps both windows of a key on the same cluster slot
+	prefix := cacheRateLimit + ":{" + key + "}:"
+
+	reply, err := redis.RunScript(ctx, slidingWindowScript,
+		[]string{prefix + strconv.FormatInt(index-1, 10), prefix + strconv.FormatInt(index, 10)},
+		limit,
+		strconv.FormatFloat(ratelimit.Weight(elapsed, window), 'f', -1, 64),
+		// Keep the counter while it can still weigh in as the previous window
+		(2 * window).Milliseconds(),
+	)
+	if err != nil {
+		return model.RateLimitResult{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return model.RateLimitResult{}, fmt.Errorf("unexpected rate limit reply: %v", reply)
+	}
+	previous, _ := values[0].(int64)
+	current, _ := values[1].(int64)
+	allowed, _ := values[2].(int64)
+
+	return model.RateLimitResult(ratelimit.Evaluate(previous, current, allowed == 1, limit, window, elapsed)), nil
+}
@@ -1,7 +1,10 @@
 package redis_outbound_adapter
 
 import (
+	"context"
+
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/redis"
 )
 
 type adapter struct {
@@ -14,3 +17,23 @@ func NewAdapter() outbound_port.CachePort {
 func (s *adapter) Client() outbound_port.ClientCachePort {
 	return NewClientAdapter()
 }
+
+func (s *adapter) Token() outbound_port.TokenCachePort {
+	return NewTokenAdapter()
+}
+
+func (s *adapter) Nonce() outbound_port.NonceCachePort {
+	return NewNonceAdapter()
+}
+
+func (s *adapter) RateLimit() outbound_port.RateLimitCachePort {
+	return NewRateLimitAdapter()
+}
+
+func (s *adapter) Lockout() outbound_port.LockoutCachePort {
+	return NewLockoutAdapter()
+}
+
+func (s *adapter) Ping(ctx context.Context) error {
+	return redis.Ping(ctx)
+}
@@ -0,0 +1,50 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/metrics"
+	"go-template/utils/redis"
+)
+
+const cacheToken = "token"
+
+type tokenAdapter struct{}
+
+func NewTokenAdapter() outbound_port.TokenCachePort {
+	return &tokenAdapter{}
+}
+
+func (adapter *tokenAdapter) Set(ctx context.Context, tokenHash string, data model.TokenIntrospection, ttl time.Duration) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return redis.SetWithTTL(ctx, cacheToken+":"+tokenHash, string(bytes), ttl)
+}
+
+func (adapter *tokenAdapter) Get(ctx context.Context, tokenHash string) (model.TokenIntrospection, error) {
+	var data model.TokenIntrospection
+	result, err := redis.Get(ctx, cacheToken+":"+tokenHash)
+	if err != nil {
+		if err == goredis.Nil {
+			metrics.ObserveCacheLookup(cacheToken, metrics.ResultMiss)
+		} else {
+			metrics.ObserveCacheLookup(cacheToken, metrics.ResultError)
+		}
+		return model.TokenIntrospection{}, err
+	}
+	metrics.ObserveCacheLookup(cacheToken, metrics.ResultHit)
+
+	err = json.Unmarshal([]byte(result), &data)
+	if err != nil {
+		return model.TokenIntrospection{}, err
+	}
+	return data, nil
+}
@@ -15,9 +15,9 @@ func NewClientWorkflowAdapter() outbound_port.ClientWorkflowPort {
 	return &clientWorkflowAdapter{}
 }
 
-func (g *clientWorkflowAdapter) StartUpsert(input model.ClientInput) error {
+func (g *clientWorkflowAdapter) StartUpsert(ctx context.Context, input model.ClientInput) error {
 	namespace := os.Getenv("WORKFLOW_NAMESPACE")
-	_, err := temporal.ExecuteWorkflow(context.Background(), namespace, model.UpsertClientWorkflowName, input)
+	_, err := temporal.ExecuteWorkflow(ctx, namespace, model.UpsertClientWorkflowName, input)
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,10 @@
 package temporal_outbound_adapter
 
 import (
+	"context"
+
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/temporal"
 )
 
 type adapter struct{}
@@ -13,3 +16,7 @@ func NewAdapter() outbound_port.WorkflowPort {
 func (a *adapter) Client() outbound_port.ClientWorkflowPort {
 	return NewClientWorkflowAdapter()
 }
+
+func (a *adapter) Ping(ctx context.Context) error {
+	return temporal.CheckHealth(ctx)
+}
@@ -2,16 +2,22 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	command_inbound_adapter "go-template/internal/adapter/inbound/command"
 	gin_inbound_adapter "go-template/internal/adapter/inbound/gin"
+	grpc_inbound_adapter "go-template/internal/adapter/inbound/grpc"
 	rabbitmq_inbound_adapter "go-template/internal/adapter/inbound/rabbitmq"
 	temporal_inbound_adapter "go-template/internal/adapter/inbound/temporal"
 	postgres_outbound_adapter "go-template/internal/adapter/outbound/postgres"
@@ -23,14 +29,18 @@ import (
 	outbound_port "go-template/internal/port/outbound"
 	"go-template/utils"
 	"go-template/utils/activity"
+	"go-template/utils/apikey"
 	"go-template/utils/database"
 	"go-template/utils/log"
+	"go-template/utils/mtls"
 	"go-template/utils/rabbitmq"
 	"go-template/utils/redis"
+	"go-template/utils/tracing"
 )
 
 var databaseDriverList = []string{"postgres"}
 var httpDriverList = []string{"gin"}
+var grpcDriverList = []string{"grpc"}
 var messageDriverList = []string{"rabbitmq"}
 var workflowDriverList = []string{"temporal"}
 var outboundDatabaseDriver string
@@ -38,12 +48,14 @@ var outboundMessageDriver string
 var outboundCacheDriver string
 var outboundWorkflowDriver string
 var inboundHttpDriver string
+var inboundGrpcDriver string
 var inboundMessageDriver string
 var inboundWorkflowDriver string
 
 type App struct {
-	ctx    context.Context
-	domain domain.Domain
+	ctx             context.Context
+	domain          domain.Domain
+	shutdownTracing tracing.ShutdownFunc
 }
 
 func NewApp() *App {
@@ -56,8 +68,14 @@ func NewApp() *App {
 	outboundCacheDriver = os.Getenv("OUTBOUND_CACHE_DRIVER")
 	outboundWorkflowDriver = os.Getenv("OUTBOUND_WORKFLOW_DRIVER")
 	inboundHttpDriver = os.Getenv("INBOUND_HTTP_DRIVER")
+	inboundGrpcDriver = os.Getenv("INBOUND_GRPC_DRIVER")
 	inboundMessageDriver = os.Getenv("INBOUND_MESSAGE_DRIVER")
 	inboundWorkflowDriver = os.Getenv("INBOUND_WORKFLOW_DRIVER")
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		log.WithContext(ctx).Error("failed to init tracing", err)
+		os.Exit(1)
+	}
 	domain := domain.NewDomain(
 		databaseOutbound(ctx),
 		messageOutbound(ctx),
@@ -66,15 +84,21 @@ func NewApp() *App {
 	)
 
 	return &App{
-		ctx:    ctx,
-		domain: domain,
+		ctx:             ctx,
+		domain:          domain,
+		shutdownTracing: shutdownTracing,
 	}
 }
 
 func (a *App) Run(option string) {
+	defer a.Close()
+	// Every mode hashes bearer keys, CLI commands included
+	a.requirePepper()
 	switch option {
 	case "http":
 		a.httpInbound()
+	case "grpc":
+		a.grpcInbound()
 	case "message":
 		a.messageInbound()
 	case "workflow":
@@ -84,6 +108,15 @@ func (a *App) Run(option string) {
 	}
 }
 
+// requirePepper stops the app from starting without BEARER_KEY_PEPPER, which
+// would store hashes that never match once the pepper is set
+func (a *App) requirePepper() {
+	if err := apikey.RequirePepper(); err != nil {
+		log.WithContext(a.ctx).Error("bearer key pepper is required", err)
+		os.Exit(1)
+	}
+}
+
 func databaseOutbound(ctx context.Context) outbound_port.DatabasePort {
 	if !utils.IsInList(databaseDriverList, outboundDatabaseDriver) {
 		log.WithContext(ctx).Error("database driver is not supported")
@@ -149,28 +182,128 @@ func (a *App) httpInbound() {
 		os.Exit(1)
 	}
 
+	var server *http.Server
 	switch inboundHttpDriver {
 	case "gin":
-		app := gin.Default()
+		app, err := gin_inbound_adapter.NewEngine()
+		if err != nil {
+			log.WithContext(ctx).Error("invalid trusted proxies", err)
+			os.Exit(1)
+		}
 		inboundHttpAdapter := gin_inbound_adapter.NewAdapter(a.domain)
 		gin_inbound_adapter.InitRoute(ctx, app, inboundHttpAdapter)
-		go func() {
-			if err := app.Run(":" + os.Getenv("SERVER_PORT")); err != nil {
-				log.WithContext(ctx).Error("failed to listen and serve", err)
-				os.Exit(1)
-			}
-		}()
+		server = &http.Server{
+			Addr:    ":" + os.Getenv("SERVER_PORT"),
+			Handler: app,
+		}
+	}
+
+	tlsConfig, err := mtls.ConfigFromEnv()
+	if err != nil {
+		log.WithContext(ctx).Error("invalid tls configuration", err)
+		os.Exit(1)
+	}
+	if tlsConfig.Enabled() {
+		// Certificates are read through the reloader, so rotated files are served without a restart
+		reloader, err := mtls.NewReloader(tlsConfig)
+		if err != nil {
+			log.WithContext(ctx).Error("failed to load tls certificate", err)
+			os.Exit(1)
+		}
+		server.TLSConfig = reloader.TLSConfig()
 	}
 
-	ctx, shutdown := context.WithTimeout(ctx, 5*time.Second)
-	defer shutdown()
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithContext(ctx).Error("failed to listen and serve", err)
+			os.Exit(1)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
+	log.WithContext(ctx).Info("http server shutting down")
+
+	// Stop accepting new connections and wait for in-flight requests to drain
+	shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout())
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.WithContext(ctx).Error("failed to drain http server", err)
+	}
 
 	log.WithContext(ctx).Info("http server stopped")
 }
 
+func (a *App) grpcInbound() {
+	ctx := a.ctx
+	if !utils.IsInList(grpcDriverList, inboundGrpcDriver) {
+		log.WithContext(ctx).Error("grpc driver is not supported")
+		os.Exit(1)
+	}
+
+	tlsConfig, err := mtls.ConfigFromEnv()
+	if err != nil {
+		log.WithContext(ctx).Error("invalid tls configuration", err)
+		os.Exit(1)
+	}
+	var options []grpc.ServerOption
+	if tlsConfig.Enabled() {
+		reloader, err := mtls.NewReloader(tlsConfig)
+		if err != nil {
+			log.WithContext(ctx).Error("failed to load tls certificate", err)
+			os.Exit(1)
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	}
+
+	var server *grpc.Server
+	switch inboundGrpcDriver {
+	case "grpc":
+		inboundGrpcAdapter := grpc_inbound_adapter.NewAdapter(a.domain)
+		server = grpc_inbound_adapter.InitRoute(ctx, inboundGrpcAdapter, options...)
+	}
+
+	listener, err := net.Listen("tcp", ":"+os.Getenv("GRPC_PORT"))
+	if err != nil {
+		log.WithContext(ctx).Error("failed to listen", err)
+		os.Exit(1)
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.WithContext(ctx).Error("failed to serve grpc", err)
+			os.Exit(1)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	log.WithContext(ctx).Info("grpc server shutting down")
+
+	// Stop accepting new calls and wait for in-flight ones, cancelling them after the timeout
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout()):
+		log.WithContext(ctx).Warn("grpc server drain timed out")
+		server.Stop()
+	}
+
+	log.WithContext(ctx).Info("grpc server stopped")
+}
+
 func (a *App) messageInbound() {
 	ctx := a.ctx
 	if !utils.IsInList(messageDriverList, inboundMessageDriver) {
@@ -205,6 +338,36 @@ func (a *App) workflowInbound() {
 	}
 }
 
+// Close releases the outbound connections opened by NewApp
+func (a *App) Close() {
+	ctx := a.ctx
+	// Buffered last used timestamps need the database, so flush them first
+	if err := a.domain.Client().FlushUsage(ctx); err != nil {
+		log.WithContext(ctx).Error("failed to flush client usage", err)
+	}
+	if err := database.Close(); err != nil {
+		log.WithContext(ctx).Error("failed to close database", err)
+	}
+	if err := rabbitmq.Close(); err != nil {
+		log.WithContext(ctx).Error("failed to close rabbitmq", err)
+	}
+	if err := redis.Close(); err != nil {
+		log.WithContext(ctx).Error("failed to close redis", err)
+	}
+
+	// Flush spans last so shutdown activity above is exported too
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := a.shutdownTracing(shutdownCtx); err != nil {
+		log.WithContext(ctx).Error("failed to shutdown tracing", err)
+	}
+}
+
+// shutdownTimeout returns how long the http and grpc servers wait for in-flight requests on shutdown
+func shutdownTimeout() time.Duration {
+	return utils.GetEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second)
+}
+
 func configureLogging() {
 	// Zap logger is initialized in log package init()
 	// No additional configuration needed here; it auto-detects APP_MODE
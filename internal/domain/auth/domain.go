@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/palantir/stacktrace"
+
+	"go-template/internal/domain/client"
+	"go-template/internal/domain/lockout"
+	"go-template/internal/domain/token"
+	"go-template/internal/model"
+	"go-template/utils/apperror"
+	"go-template/utils/internalkey"
+	"go-template/utils/jwt"
+)
+
+type AuthDomain interface {
+	AuthenticateClient(ctx context.Context, credentials model.Credentials) (model.Principal, error)
+	AuthenticateSignature(ctx context.Context, credentials model.Credentials) (model.Principal, error)
+	AuthenticateInternal(ctx context.Context, credentials model.Credentials) (model.Principal, internalkey.Credential, error)
+}
+
+type authDomain struct {
+	client  client.ClientDomain
+	token   token.TokenDomain
+	lockout lockout.LockoutDomain
+}
+
+// NewAuthDomain resolves callers through the shared client, token and lockout
+// domains, so every transport batches usage and counts failures in one place
+func NewAuthDomain(
+	clientDomain client.ClientDomain,
+	tokenDomain token.TokenDomain,
+	lockoutDomain lockout.LockoutDomain,
+) AuthDomain {
+	return &authDomain{
+		client:  clientDomain,
+		token:   tokenDomain,
+		lockout: lockoutDomain,
+	}
+}
+
+// AuthenticateClient identifies the caller with the configured AUTH_DRIVER.
+// Bearer key and signature callers are locked out after too many failures
+func (s *authDomain) AuthenticateClient(ctx context.Context, credentials model.Credentials) (model.Principal, error) {
+	authDriver := os.Getenv("AUTH_DRIVER")
+	switch authDriver {
+	case "mtls":
+		return s.authenticateCertificate(ctx, credentials)
+	case "hmac":
+		return s.AuthenticateSignature(ctx, credentials)
+	}
+
+	if credentials.BearerToken == "" {
+		return model.Principal{}, apperror.NewUnauthorized("Unauthorized")
+	}
+
+	switch authDriver {
+	case "jwt":
+		claims, err := jwt.GetJWTClaimsWithURL(credentials.BearerToken, os.Getenv("AUTH_JWKS_URL"))
+		if err != nil {
+			return model.Principal{}, apperror.NewUnauthorized("Unauthorized: %s", err)
+		}
+
+		issuer, _ := claims.GetIssuer()
+		return model.Principal{
+			Subject: jwt.Subject(claims),
+			Source:  model.PrincipalSourceJWT,
+			Scopes:  jwt.Scopes(claims),
+			Issuer:  issuer,
+			Claims:  claims,
+		}, nil
+	case "introspection":
+		principal, active, err := s.token.Introspect(ctx, credentials.BearerToken)
+		if err != nil {
+			return model.Principal{}, stacktrace.Propagate(err, "introspect token error")
+		}
+
+		if !active {
+			return model.Principal{}, apperror.NewUnauthorized("Unauthorized")
+		}
+		return principal, nil
+	default:
+		if err := s.lockedOut(ctx, model.LockoutScopeClient, credentials.IP); err != nil {
+			return model.Principal{}, err
+		}
+
+		client, exists, err := s.client.Authenticate(ctx, credentials.BearerToken)
+		if err != nil {
+			return model.Principal{}, stacktrace.Propagate(err, "authenticate client error")
+		}
+		return s.settle(ctx, credentials.IP, client, exists, model.PrincipalSourceClient)
+	}
+}
+
+// authenticateCertificate identifies the caller from the client certificate
+// verified during the TLS handshake instead of a bearer token
+func (s *authDomain) authenticateCertificate(ctx context.Context, credentials model.Credentials) (model.Principal, error) {
+	if len(credentials.CertificateIdentities) == 0 {
+		return model.Principal{}, apperror.NewUnauthorized("Unauthorized: client certificate required")
+	}
+
+	client, exists, err := s.client.AuthenticateCertificate(ctx, credentials.CertificateIdentities)
+	if err != nil {
+		return model.Principal{}, stacktrace.Propagate(err, "authenticate certificate error")
+	}
+
+	if !exists {
+		return model.Principal{}, apperror.NewUnauthorized("Unauthorized")
+	}
+	return clientPrincipal(client, model.PrincipalSourceCertificate), nil
+}
+
+// AuthenticateSignature identifies a caller that signed the request with its
+// HMAC signing secret instead of sending a bearer key
+func (s *authDomain) AuthenticateSignature(ctx context.Context, credentials model.Credentials) (model.Principal, error) {
+	if credentials.Signed == nil {
+		return model.Principal{}, apperror.NewUnauthorized("Unauthorized: signed requests are only accepted over HTTP")
+	}
+
+	if err := s.lockedOut(ctx, model.LockoutScopeClient, credentials.IP); err != nil {
+		return model.Principal{}, err
+	}
+
+	client, exists, err := s.client.VerifySignature(ctx, *credentials.Signed)
+	if err != nil {
+		return model.Principal{}, stacktrace.Propagate(err, "verify signature error")
+	}
+	return s.settle(ctx, credentials.IP, client, exists, model.PrincipalSourceSignature)
+}
+
+// settle counts the attempt towards the caller's lockout and builds the
+// principal of a client that was found
+func (s *authDomain) settle(ctx context.Context, ip string, client model.Client, exists bool, source string) (model.Principal, error) {
+	if !exists {
+		s.lockout.Fail(ctx, model.LockoutScopeClient, ip)
+		return model.Principal{}, apperror.NewUnauthorized("Unauthorized")
+	}
+
+	s.lockout.Succeed(ctx, model.LockoutScopeClient, ip)
+	return clientPrincipal(client, source), nil
+}
+
+// AuthenticateInternal identifies the caller by one of the named internal
+// keys. The credential is returned so the adapter can check its routes
+func (s *authDomain) AuthenticateInternal(ctx context.Context, credentials model.Credentials) (model.Principal, internalkey.Credential, error) {
+	if err := s.lockedOut(ctx, model.LockoutScopeInternal, credentials.IP); err != nil {
+		return model.Principal{}, internalkey.Credential{}, err
+	}
+
+	if credentials.BearerToken == "" {
+		return model.Principal{}, internalkey.Credential{}, apperror.NewUnauthorized("Unauthorized")
+	}
+
+	credential, ok := internalkey.SharedStore().Match(ctx, credentials.BearerToken)
+	if !ok {
+		s.lockout.Fail(ctx, model.LockoutScopeInternal, credentials.IP)
+		return model.Principal{}, internalkey.Credential{}, apperror.NewUnauthorized("Unauthorized")
+	}
+
+	s.lockout.Succeed(ctx, model.LockoutScopeInternal, credentials.IP)
+	return model.Principal{
+		Subject: credential.Name,
+		Source:  model.PrincipalSourceInternal,
+		Name:    credential.Name,
+	}, credential, nil
+}
+
+// lockedOut rejects callers locked out of scope after too many failed attempts
+func (s *authDomain) lockedOut(ctx context.Context, scope string, ip string) error {
+	if lockedFor := s.lockout.LockedFor(ctx, scope, ip); lockedFor > 0 {
+		return apperror.NewTooManyRequests(lockedFor)
+	}
+	return nil
+}
+
+func clientPrincipal(client model.Client, source string) model.Principal {
+	return model.Principal{
+		Subject:   strconv.Itoa(client.ID),
+		Source:    source,
+		Scopes:    client.Scopes,
+		ClientID:  client.ID,
+		Name:      client.Name,
+		RateLimit: client.RateLimit,
+	}
+}
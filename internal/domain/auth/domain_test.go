@@ -0,0 +1,134 @@
+package auth_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apikey"
+	"go-template/utils/apperror"
+	"go-template/utils/internalkey"
+)
+
+func TestAuth(t *testing.T) {
+	Convey("Test Auth", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
+		mockLockoutCachePort := mock_outbound_port.NewMockLockoutCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Lockout().Return(mockLockoutCachePort).AnyTimes()
+		// Last used timestamps of authenticated clients are flushed in the background
+		mockClientDatabasePort.EXPECT().UpdateLastUsed(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+		authDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort).Auth()
+		ctx := context.Background()
+		credentials := model.Credentials{IP: "192.0.2.1", BearerToken: "valid-client-key"}
+
+		Convey("AuthenticateClient", func() {
+			os.Setenv("AUTH_DRIVER", "database")
+			defer os.Unsetenv("AUTH_DRIVER")
+
+			Convey("Missing bearer token", func() {
+				_, err := authDomain.AuthenticateClient(ctx, model.Credentials{IP: "192.0.2.1"})
+				So(apperror.Code(err), ShouldEqual, apperror.Unauthorized)
+			})
+
+			Convey("Known key builds the client principal and resets the failures", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{ID: 7, ClientInput: model.ClientInput{
+					Name:          "billing",
+					BearerKeyHash: apikey.Hash("valid-client-key"),
+					RateLimit:     5,
+				}}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().ResetFailures(gomock.Any(), "client:192.0.2.1").Return(nil).Times(1)
+
+				principal, err := authDomain.AuthenticateClient(ctx, credentials)
+				So(err, ShouldBeNil)
+				So(principal.Subject, ShouldEqual, "7")
+				So(principal.Source, ShouldEqual, model.PrincipalSourceClient)
+				So(principal.Name, ShouldEqual, "billing")
+				So(principal.RateLimit, ShouldEqual, 5)
+			})
+
+			Convey("Unknown key counts as a failure", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "client:192.0.2.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, err := authDomain.AuthenticateClient(ctx, credentials)
+				So(apperror.Code(err), ShouldEqual, apperror.Unauthorized)
+			})
+
+			Convey("Locked out callers never reach the client lookup", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "client:192.0.2.1").Return(90*time.Second, nil).Times(1)
+
+				_, err := authDomain.AuthenticateClient(ctx, credentials)
+				So(apperror.Code(err), ShouldEqual, apperror.TooManyRequests)
+				So(apperror.RetryAfter(err), ShouldEqual, 90*time.Second)
+			})
+
+			Convey("Certificate driver requires a verified certificate", func() {
+				os.Setenv("AUTH_DRIVER", "mtls")
+
+				_, err := authDomain.AuthenticateClient(ctx, credentials)
+				So(apperror.Code(err), ShouldEqual, apperror.Unauthorized)
+			})
+
+			Convey("Signature driver requires a signed request", func() {
+				os.Setenv("AUTH_DRIVER", "hmac")
+
+				_, err := authDomain.AuthenticateClient(ctx, credentials)
+				So(apperror.Code(err), ShouldEqual, apperror.Unauthorized)
+				So(apperror.Message(err), ShouldContainSubstring, "only accepted over HTTP")
+			})
+		})
+
+		Convey("AuthenticateInternal", func() {
+			os.Setenv("INTERNAL_KEYS", `[{"name": "deployer", "key_hashes": ["`+internalkey.Hash("deployer-key")+`"], "routes": ["/internal/client-upsert"]}]`)
+			defer os.Unsetenv("INTERNAL_KEYS")
+
+			Convey("Known key returns its credential", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "internal:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockLockoutCachePort.EXPECT().ResetFailures(gomock.Any(), "internal:192.0.2.1").Return(nil).Times(1)
+
+				principal, credential, err := authDomain.AuthenticateInternal(ctx, model.Credentials{IP: "192.0.2.1", BearerToken: "deployer-key"})
+				So(err, ShouldBeNil)
+				So(principal.Source, ShouldEqual, model.PrincipalSourceInternal)
+				So(principal.Name, ShouldEqual, "deployer")
+				So(credential.Allows("POST", "/internal/client-upsert"), ShouldBeTrue)
+			})
+
+			Convey("Unknown key counts as a failure", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "internal:192.0.2.1").Return(time.Duration(0), nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), "internal:192.0.2.1", gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := authDomain.AuthenticateInternal(ctx, model.Credentials{IP: "192.0.2.1", BearerToken: "wrong-key"})
+				So(apperror.Code(err), ShouldEqual, apperror.Unauthorized)
+			})
+
+			Convey("Locked out callers are rejected even with the right key", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), "internal:192.0.2.1").Return(time.Minute, nil).Times(1)
+
+				_, _, err := authDomain.AuthenticateInternal(ctx, model.Credentials{IP: "192.0.2.1", BearerToken: "deployer-key"})
+				So(apperror.Code(err), ShouldEqual, apperror.TooManyRequests)
+			})
+		})
+	})
+}
@@ -2,21 +2,44 @@ package client
 
 import (
 	"context"
+	"os"
+	"time"
 
 	"github.com/palantir/stacktrace"
 	"github.com/redis/go-redis/v9"
 
 	"go-template/internal/model"
 	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils"
+	"go-template/utils/apikey"
+	"go-template/utils/apperror"
+	"go-template/utils/log"
+	"go-template/utils/validation"
 )
 
+// defaultPageSize applies when FindByFilter is called without a limit
+const defaultPageSize = 50
+
 type ClientDomain interface {
 	Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error)
-	FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, error)
+	Create(ctx context.Context, input model.ClientInput) (model.Client, error)
+	Update(ctx context.Context, id int, input model.ClientInput) (model.Client, error)
+	Patch(ctx context.Context, id int, patch model.ClientPatch) (model.Client, error)
+	FindByID(ctx context.Context, id int) (model.Client, error)
+	// FindByFilter returns one page of matches; an empty filter lists every client
+	FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, model.Page, error)
+	DeleteByID(ctx context.Context, id int) error
 	DeleteByFilter(ctx context.Context, filter model.ClientFilter) error
 	PublishUpsert(ctx context.Context, inputs []model.ClientInput) error
 	IsExists(ctx context.Context, bearerKey string) (bool, error)
+	Authenticate(ctx context.Context, bearerKey string) (model.Client, bool, error)
+	AuthenticateCertificate(ctx context.Context, identities []string) (model.Client, bool, error)
+	IssueSigningSecret(ctx context.Context, input model.ClientSigningSecretInput) (model.Client, error)
+	VerifySignature(ctx context.Context, request model.SignedRequest) (model.Client, bool, error)
+	RotateKey(ctx context.Context, input model.ClientRotateInput) (model.Client, error)
+	RevokeByFilter(ctx context.Context, filter model.ClientFilter) error
 	StartUpsert(ctx context.Context, input model.ClientInput) error
+	FlushUsage(ctx context.Context) error
 }
 
 type clientDomain struct {
@@ -24,6 +47,7 @@ type clientDomain struct {
 	messagePort  outbound_port.MessagePort
 	cachePort    outbound_port.CachePort
 	workflowPort outbound_port.WorkflowPort
+	usage        *usageRecorder
 }
 
 func NewClientDomain(
@@ -37,116 +61,669 @@ func NewClientDomain(
 		messagePort:  messagePort,
 		cachePort:    cachePort,
 		workflowPort: workflowPort,
+		usage:        newUsageRecorder(databasePort),
 	}
 }
 
 func (s *clientDomain) Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error) {
 	if len(inputs) == 0 {
-		return nil, stacktrace.NewError("inputs is empty")
+		return nil, apperror.NewValidation("inputs is empty")
+	}
+	err := validation.Slice(inputs)
+	if err != nil {
+		return nil, err
 	}
 
-	var filter model.ClientFilter
+	generated := prepareInputs(inputs)
+
+	return s.write(ctx, inputs, generated, func(tx outbound_port.DatabasePort) error {
+		err := tx.Client().Upsert(ctx, inputs)
+		if err != nil {
+			return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "upsert client error")
+		}
+		return nil
+	})
+}
+
+// Create adds a single client. Unlike Upsert, a key or certificate subject
+// that is already taken is a conflict rather than an update
+func (s *clientDomain) Create(ctx context.Context, input model.ClientInput) (model.Client, error) {
+	err := validation.Struct(input)
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	inputs := []model.ClientInput{input}
+	generated := prepareInputs(inputs)
+
+	results, err := s.write(ctx, inputs, generated, func(tx outbound_port.DatabasePort) error {
+		err := tx.Client().Insert(ctx, inputs[0])
+		if err != nil {
+			return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "insert client error")
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Client{}, err
+	}
+	if len(results) == 0 {
+		return model.Client{}, stacktrace.NewError("created client not found")
+	}
+
+	return results[0], nil
+}
+
+// prepareInputs hashes the keys of inputs, generating the missing ones. The
+// generated keys are returned by hash, to be handed back once by write
+func prepareInputs(inputs []model.ClientInput) map[string]string {
+	generated := make(map[string]string)
 	for i := range inputs {
+		supplied := inputs[i].BearerKey != ""
 		model.ClientPrepare(&inputs[i])
-		filter.Names = append(filter.Names, inputs[i].Name)
+		if !supplied {
+			generated[inputs[i].BearerKeyHash] = inputs[i].BearerKey
+		}
 	}
+	return generated
+}
 
-	databaseClientPort := s.databasePort.Client()
-	err := databaseClientPort.Upsert(inputs)
+// write runs insert and stores the scopes of inputs in one transaction, so a
+// client is never left with stale scopes. It then reads back the clients just
+// written and drops cached copies of them
+func (s *clientDomain) write(ctx context.Context, inputs []model.ClientInput, generated map[string]string, insert func(tx outbound_port.DatabasePort) error) ([]model.Client, error) {
+	var filter model.ClientFilter
+	for _, input := range inputs {
+		filter.BearerKeyHashes = append(filter.BearerKeyHashes, input.BearerKeyHash)
+	}
+
+	// Only inputs that carry scopes replace what is stored
+	scopes := make(map[string][]string)
+	for _, input := range inputs {
+		if input.Scopes != nil {
+			scopes[input.BearerKeyHash] = input.Scopes
+		}
+	}
+
+	out, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		err := insert(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		results, err := tx.Client().FindByFilter(ctx, filter, true)
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+		}
+
+		for _, result := range results {
+			if granted, ok := scopes[result.BearerKeyHash]; ok {
+				err = tx.ClientScope().Replace(ctx, result.ID, granted)
+				if err != nil {
+					return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "replace client scope error")
+				}
+			}
+		}
+
+		return results, nil
+	})
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "upsert client error")
+		return nil, stacktrace.Propagate(err, "write client error")
 	}
+	results := out.([]model.Client)
 
-	results, err := databaseClientPort.FindByFilter(filter, true)
+	err = s.attachScopes(ctx, results)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "find client by filter error")
+		return nil, err
+	}
+
+	// Updated rows may have a new name or expiry, and new keys may have been
+	// cached as unknown, so cached copies are dropped
+	err = s.cachePort.Client().DeleteMany(ctx, bearerKeyHashes(results))
+	if err != nil {
+		return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "invalidate client cache error")
+	}
+
+	// Generated keys are handed back once here; only their hash is stored
+	for i := range results {
+		results[i].BearerKey = generated[results[i].BearerKeyHash]
 	}
 
 	return results, nil
 }
 
-func (s *clientDomain) FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, error) {
-	if filter.IsEmpty() {
-		return nil, stacktrace.NewError("filter is empty")
+// Update replaces the editable fields of a client; scopes left out are removed
+func (s *clientDomain) Update(ctx context.Context, id int, input model.ClientInput) (model.Client, error) {
+	if input.BearerKey != "" {
+		return model.Client{}, apperror.NewInvalidFields([]apperror.FieldError{
+			{Field: "bearer_key", Rule: "excluded", Message: "cannot be changed, rotate the key instead"},
+		})
+	}
+	if input.Scopes == nil {
+		input.Scopes = []string{}
+	}
+
+	patch := model.ClientPatch{
+		Name:               &input.Name,
+		CertificateSubject: &input.CertificateSubject,
+		RateLimit:          &input.RateLimit,
+		Scopes:             input.Scopes,
 	}
+	return s.update(ctx, id, func(v *model.ClientInput) {
+		model.ClientPatchApply(v, patch)
+		// Unlike a patch, leaving the expiry out removes it
+		v.ExpiresAt = input.ExpiresAt
+	})
+}
+
+// Patch changes only the fields the patch carries
+func (s *clientDomain) Patch(ctx context.Context, id int, patch model.ClientPatch) (model.Client, error) {
+	return s.update(ctx, id, func(v *model.ClientInput) {
+		model.ClientPatchApply(v, patch)
+	})
+}
+
+func (s *clientDomain) update(ctx context.Context, id int, apply func(v *model.ClientInput)) (model.Client, error) {
+	if id == 0 {
+		return model.Client{}, apperror.NewValidation("id is empty")
+	}
+
+	out, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		clients, err := tx.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{id}}, true)
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+		}
+		if len(clients) == 0 {
+			return nil, apperror.NewNotFound("client not found")
+		}
+
+		client := clients[0]
+		apply(&client.ClientInput)
+		err = validation.Struct(client.ClientInput)
+		if err != nil {
+			return nil, err
+		}
+		client.UpdatedAt = time.Now()
+
+		err = tx.Client().Update(ctx, client)
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "update client error")
+		}
+
+		if client.Scopes != nil {
+			err = tx.ClientScope().Replace(ctx, client.ID, client.Scopes)
+			if err != nil {
+				return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "replace client scope error")
+			}
+		}
+
+		return client, nil
+	})
+	if err != nil {
+		return model.Client{}, stacktrace.Propagate(err, "update client error")
+	}
+
+	results := []model.Client{out.(model.Client)}
+	// The cached copy would keep the old name, expiry and rate limit
+	err = s.cachePort.Client().Delete(ctx, results[0].BearerKeyHash)
+	if err != nil {
+		return model.Client{}, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "invalidate client cache error")
+	}
+
+	err = s.attachScopes(ctx, results)
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	return results[0], nil
+}
+
+// FindByID returns a single client, or a not found error
+func (s *clientDomain) FindByID(ctx context.Context, id int) (model.Client, error) {
+	filter := model.ClientFilter{IDs: []int{id}}
+	err := validation.Struct(filter)
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	results, err := s.databasePort.Client().FindByFilter(ctx, filter, false)
+	if err != nil {
+		return model.Client{}, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+	}
+	if len(results) == 0 {
+		return model.Client{}, apperror.NewNotFound("client not found")
+	}
+
+	err = s.attachScopes(ctx, results)
+	if err != nil {
+		return model.Client{}, err
+	}
+
+	return results[0], nil
+}
+
+func (s *clientDomain) FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, model.Page, error) {
+	err := validation.Struct(filter)
+	if err != nil {
+		return nil, model.Page{}, err
+	}
+	if filter.Cursor != "" {
+		filter.AfterID, err = model.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, model.Page{}, apperror.NewInvalidFields([]apperror.FieldError{
+				{Field: "cursor", Rule: "cursor", Message: "is not the next_cursor of a previous page"},
+			})
+		}
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	model.ClientFilterPrepare(&filter)
+
+	// One row past the limit tells whether another page follows
+	limit := filter.Limit
+	filter.Limit++
 
 	databaseClientPort := s.databasePort.Client()
-	results, err := databaseClientPort.FindByFilter(filter, false)
+	results, err := databaseClientPort.FindByFilter(ctx, filter, false)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "find client by filter error")
+		return nil, model.Page{}, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
 	}
 
-	return results, nil
+	var page model.Page
+	if len(results) > limit {
+		results = results[:limit]
+		page.NextCursor = model.EncodeCursor(results[limit-1].ID)
+	}
+
+	page.Total, err = databaseClientPort.CountByFilter(ctx, filter)
+	if err != nil {
+		return nil, model.Page{}, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "count client by filter error")
+	}
+
+	err = s.attachScopes(ctx, results)
+	if err != nil {
+		return nil, model.Page{}, err
+	}
+
+	return results, page, nil
 }
 
 func (s *clientDomain) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
 	if filter.IsEmpty() {
-		return stacktrace.NewError("filter is empty")
+		return apperror.NewValidation("filter is empty")
+	}
+	err := validation.Struct(filter)
+	if err != nil {
+		return err
 	}
 
-	databaseClientPort := s.databasePort.Client()
-	err := databaseClientPort.DeleteByFilter(filter)
+	_, err = s.deleteClients(ctx, filter)
+	return err
+}
+
+// DeleteByID deletes a single client, or reports that it does not exist
+func (s *clientDomain) DeleteByID(ctx context.Context, id int) error {
+	err := validation.Struct(model.ClientFilter{IDs: []int{id}})
 	if err != nil {
-		return stacktrace.Propagate(err, "delete client by filter error")
+		return err
+	}
+
+	clients, err := s.deleteClients(ctx, model.ClientFilter{IDs: []int{id}})
+	if err != nil {
+		return err
+	}
+	if len(clients) == 0 {
+		return apperror.NewNotFound("client not found")
 	}
 
 	return nil
 }
 
+// deleteClients deletes the clients matching filter and returns them
+func (s *clientDomain) deleteClients(ctx context.Context, filter model.ClientFilter) ([]model.Client, error) {
+	model.ClientFilterPrepare(&filter)
+	model.ClientFilterUnpaged(&filter)
+
+	databaseClientPort := s.databasePort.Client()
+	clients, err := databaseClientPort.FindByFilter(ctx, filter, false)
+	if err != nil {
+		return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+	}
+
+	err = databaseClientPort.DeleteByFilter(ctx, filter)
+	if err != nil {
+		return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "delete client by filter error")
+	}
+
+	// A cached copy would keep a deleted client passing authentication
+	err = s.cachePort.Client().DeleteMany(ctx, bearerKeyHashes(clients))
+	if err != nil {
+		return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "invalidate client cache error")
+	}
+
+	return clients, nil
+}
+
 func (s *clientDomain) PublishUpsert(ctx context.Context, inputs []model.ClientInput) error {
 	if len(inputs) == 0 {
-		return stacktrace.NewError("inputs is empty")
+		return apperror.NewValidation("inputs is empty")
+	}
+	// Rejected before publishing, so consumers never see input they cannot store
+	err := validation.Slice(inputs)
+	if err != nil {
+		return err
 	}
 
 	messageClientPort := s.messagePort.Client()
-	err := messageClientPort.PublishUpsert(inputs)
+	err = messageClientPort.PublishUpsert(ctx, inputs)
 	if err != nil {
-		return stacktrace.Propagate(err, "publish upsert client error")
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "publish upsert client error")
 	}
 
 	return nil
 }
 
 func (s *clientDomain) IsExists(ctx context.Context, bearerKey string) (bool, error) {
+	_, exists, err := s.Authenticate(ctx, bearerKey)
+	return exists, err
+}
+
+// Authenticate resolves an active client, with its scopes, from a plaintext bearer key
+func (s *clientDomain) Authenticate(ctx context.Context, bearerKey string) (model.Client, bool, error) {
 	if bearerKey == "" {
-		return false, stacktrace.NewError("bearerKey is empty")
+		return model.Client{}, false, apperror.NewValidation("bearerKey is empty")
 	}
 
-	var exists bool
+	now := time.Now()
 	cacheClientPort := s.cachePort.Client()
-	_, err := cacheClientPort.Get(bearerKey)
+	cached, err := cacheClientPort.Get(ctx, apikey.Hash(bearerKey))
+	if err == nil {
+		// Unknown keys are cached without a hash and never verify
+		if !apikey.Verify(bearerKey, cached.BearerKeyHash) || !cached.IsActive(now) {
+			return model.Client{}, false, nil
+		}
+		s.usage.Record(cached.ID, now)
+		return cached, true, nil
+	}
+	if err != redis.Nil {
+		return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "get client from cache error")
+	}
+
+	// Look up candidates by the public prefix and compare hashes in constant time
+	databaseClientPort := s.databasePort.Client()
+	clients, err := databaseClientPort.FindByFilter(ctx, model.ClientFilter{KeyPrefixes: []string{apikey.Prefix(bearerKey)}}, false)
+	if err != nil {
+		return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+	}
+
+	for _, client := range clients {
+		if !apikey.Verify(bearerKey, client.BearerKeyHash) {
+			continue
+		}
+		if !client.IsActive(now) {
+			return model.Client{}, false, nil
+		}
+
+		matched := []model.Client{client}
+		err = s.attachScopes(ctx, matched)
+		if err != nil {
+			return model.Client{}, false, err
+		}
+
+		err = cacheClientPort.Set(ctx, matched[0])
+		if err != nil {
+			return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "set client to cache error")
+		}
+		s.usage.Record(client.ID, now)
+		return matched[0], true, nil
+	}
+
+	// Superseded keys are honoured until their grace period ends, but never cached
+	keys, err := s.databasePort.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{
+		KeyPrefixes: []string{apikey.Prefix(bearerKey)},
+		ActiveAt:    now,
+	})
+	if err != nil {
+		return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client key by filter error")
+	}
+
+	for _, key := range keys {
+		if !apikey.Verify(bearerKey, key.BearerKeyHash) {
+			continue
+		}
+
+		// A revoked or expired client takes its superseded keys down with it
+		owners, err := databaseClientPort.FindByFilter(ctx, model.ClientFilter{IDs: []int{key.ClientID}}, false)
+		if err != nil {
+			return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+		}
+		if len(owners) == 0 || !owners[0].IsActive(now) {
+			return model.Client{}, false, nil
+		}
+
+		err = s.attachScopes(ctx, owners)
+		if err != nil {
+			return model.Client{}, false, err
+		}
+		s.usage.Record(key.ClientID, now)
+		return owners[0], true, nil
+	}
+
+	// Remember the miss so sprayed keys cost one cache lookup instead of
+	// database queries; upserts drop the entry like any other
+	err = cacheClientPort.SetUnknown(ctx, apikey.Hash(bearerKey), unknownKeyCacheTTL())
 	if err != nil {
-		if err == redis.Nil {
-			databaseClientPort := s.databasePort.Client()
-			exists, err = databaseClientPort.IsExists(bearerKey)
+		log.WithContext(ctx).Error("set unknown client key to cache error", err)
+	}
+
+	return model.Client{}, false, nil
+}
+
+// AuthenticateCertificate matches the identities of a client certificate, already
+// verified during the TLS handshake, against certificate_subject. Identities are
+// ordered most specific first and the first match wins
+func (s *clientDomain) AuthenticateCertificate(ctx context.Context, identities []string) (model.Client, bool, error) {
+	if len(identities) == 0 {
+		return model.Client{}, false, apperror.NewValidation("identities is empty")
+	}
+
+	clients, err := s.databasePort.Client().FindByFilter(ctx, model.ClientFilter{CertificateSubjects: identities}, false)
+	if err != nil {
+		return model.Client{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+	}
+
+	now := time.Now()
+	for _, identity := range identities {
+		for _, client := range clients {
+			if client.CertificateSubject != identity {
+				continue
+			}
+			if !client.IsActive(now) {
+				return model.Client{}, false, nil
+			}
+
+			matched := []model.Client{client}
+			err = s.attachScopes(ctx, matched)
 			if err != nil {
-				return false, stacktrace.Propagate(err, "check if client exists error")
+				return model.Client{}, false, err
 			}
+			s.usage.Record(client.ID, now)
+			return matched[0], true, nil
+		}
+	}
 
-			if exists {
-				client, findErr := databaseClientPort.FindByFilter(model.ClientFilter{BearerKeys: []string{bearerKey}}, false)
-				if findErr != nil {
-					return false, stacktrace.Propagate(findErr, "find client by filter error")
-				}
+	return model.Client{}, false, nil
+}
 
-				if len(client) > 0 {
-					setErr := cacheClientPort.Set(client[0])
-					if setErr != nil {
-						return false, stacktrace.Propagate(setErr, "set client to cache error")
-					}
-				}
-			}
-		} else {
-			return false, stacktrace.Propagate(err, "get client from cache error")
+func (s *clientDomain) RotateKey(ctx context.Context, input model.ClientRotateInput) (model.Client, error) {
+	if input.ID == 0 {
+		return model.Client{}, apperror.NewValidation("id is empty")
+	}
+
+	gracePeriod, err := rotateGracePeriod(input.GracePeriod)
+	if err != nil {
+		return model.Client{}, apperror.NewValidation("invalid grace period: %v", err)
+	}
+
+	var previousHash string
+	out, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		clients, err := tx.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{input.ID}}, true)
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+		}
+		if len(clients) == 0 {
+			return nil, apperror.NewNotFound("client not found")
+		}
+
+		current := clients[0]
+		previousHash = current.BearerKeyHash
+		now := time.Now()
+		err = tx.ClientKey().Insert(ctx, model.ClientKey{
+			ClientID:      current.ID,
+			Version:       current.KeyVersion,
+			KeyPrefix:     current.KeyPrefix,
+			BearerKeyHash: current.BearerKeyHash,
+			RotatedAt:     now,
+			ExpiresAt:     now.Add(gracePeriod),
+		})
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "insert client key error")
 		}
-	} else {
-		exists = true
+
+		rotated := current
+		rotated.KeyVersion++
+		rotated.BearerKey = apikey.Generate()
+		rotated.KeyPrefix = apikey.Prefix(rotated.BearerKey)
+		rotated.BearerKeyHash = apikey.Hash(rotated.BearerKey)
+		rotated.UpdatedAt = now
+		err = tx.Client().UpdateKey(ctx, rotated)
+		if err != nil {
+			return nil, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "update client key error")
+		}
+
+		return rotated, nil
+	})
+	if err != nil {
+		return model.Client{}, stacktrace.Propagate(err, "rotate client key error")
+	}
+
+	// The superseded key must fall through to the grace period check instead
+	// of living on in the cache; the rotation itself has already committed
+	err = s.cachePort.Client().Delete(ctx, previousHash)
+	if err != nil {
+		log.WithContext(ctx).Error("purge superseded client key from cache error", err)
+	}
+
+	return out.(model.Client), nil
+}
+
+func (s *clientDomain) RevokeByFilter(ctx context.Context, filter model.ClientFilter) error {
+	if filter.IsEmpty() {
+		return apperror.NewValidation("filter is empty")
+	}
+	err := validation.Struct(filter)
+	if err != nil {
+		return err
+	}
+	model.ClientFilterPrepare(&filter)
+	model.ClientFilterUnpaged(&filter)
+
+	databaseClientPort := s.databasePort.Client()
+	clients, err := databaseClientPort.FindByFilter(ctx, filter, false)
+	if err != nil {
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client by filter error")
+	}
+
+	err = databaseClientPort.RevokeByFilter(ctx, filter, time.Now())
+	if err != nil {
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "revoke client by filter error")
 	}
 
-	return exists, nil
+	// Cached entries would keep a revoked key working until they expire
+	err = s.cachePort.Client().DeleteMany(ctx, bearerKeyHashes(clients))
+	if err != nil {
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "invalidate client cache error")
+	}
+
+	return nil
 }
 
 func (s *clientDomain) StartUpsert(ctx context.Context, input model.ClientInput) error {
+	err := validation.Struct(input)
+	if err != nil {
+		return err
+	}
+
 	workflowClientPort := s.workflowPort.Client()
-	return workflowClientPort.StartUpsert(input)
+	return workflowClientPort.StartUpsert(ctx, input)
+}
+
+// FlushUsage writes buffered last used timestamps, e.g. before shutdown
+func (s *clientDomain) FlushUsage(ctx context.Context) error {
+	err := s.usage.Flush(ctx)
+	if err != nil {
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "flush client last used error")
+	}
+	return nil
+}
+
+// attachScopes loads the granted scopes of every client in one query
+func (s *clientDomain) attachScopes(ctx context.Context, clients []model.Client) error {
+	if len(clients) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(clients))
+	for i, client := range clients {
+		ids[i] = client.ID
+	}
+
+	scopes, err := s.databasePort.ClientScope().FindByFilter(ctx, model.ClientScopeFilter{ClientIDs: ids})
+	if err != nil {
+		return stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "find client scope by filter error")
+	}
+
+	granted := make(map[int][]string)
+	for _, scope := range scopes {
+		granted[scope.ClientID] = append(granted[scope.ClientID], scope.Scope)
+	}
+	for i := range clients {
+		clients[i].Scopes = granted[clients[i].ID]
+	}
+
+	return nil
+}
+
+func bearerKeyHashes(clients []model.Client) []string {
+	hashes := make([]string, len(clients))
+	for i, client := range clients {
+		hashes[i] = client.BearerKeyHash
+	}
+	return hashes
+}
+
+// rotateGracePeriod resolves how long a superseded key stays valid, falling
+// back to BEARER_KEY_GRACE_PERIOD and then to 24 hours
+func rotateGracePeriod(value string) (time.Duration, error) {
+	if value == "" {
+		value = os.Getenv("BEARER_KEY_GRACE_PERIOD")
+	}
+	if value == "" {
+		return 24 * time.Hour, nil
+	}
+
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if gracePeriod < 0 {
+		return 0, apperror.NewValidation("grace period is negative")
+	}
+	return gracePeriod, nil
+}
+
+// unknownKeyCacheTTL is how long a bearer key nobody owns is remembered, from
+// AUTH_UNKNOWN_KEY_CACHE_TTL and one minute by default
+func unknownKeyCacheTTL() time.Duration {
+	return utils.GetEnvDuration("AUTH_UNKNOWN_KEY_CACHE_TTL", time.Minute)
 }
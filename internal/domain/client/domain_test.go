@@ -3,6 +3,9 @@ package client_test
 import (
 	"context"
 	"errors"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,7 +15,12 @@ import (
 
 	"go-template/internal/domain"
 	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
 	mock_outbound_port "go-template/tests/mocks/port"
+	"go-template/utils/apikey"
+	"go-template/utils/apperror"
+	"go-template/utils/secretbox"
+	"go-template/utils/signature"
 )
 
 func TestClient(t *testing.T) {
@@ -27,16 +35,30 @@ func TestClient(t *testing.T) {
 		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
 
 		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockClientScopeDatabasePort := mock_outbound_port.NewMockClientScopeDatabasePort(mockCtrl)
 		mockClientMessagePort := mock_outbound_port.NewMockClientMessagePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
+		mockNonceCachePort := mock_outbound_port.NewMockNonceCachePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientScope().Return(mockClientScopeDatabasePort).AnyTimes()
+		mockClientScopeDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientScope{{ClientID: 1, Scope: "clients:read"}}, nil).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mockClientMessagePort).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockCachePort.EXPECT().Nonce().Return(mockNonceCachePort).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
 		clientDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		// txErr is what the last transaction returned; an error rolls it back
+		var txErr error
+		inTransaction := func(_ context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+			out, err := txFunc(mockDatabasePort)
+			txErr = err
+			return out, err
+		}
 
 		inputs := []model.ClientInput{
 			{
@@ -48,10 +70,24 @@ func TestClient(t *testing.T) {
 			{
 				ID: 1,
 				ClientInput: model.ClientInput{
-					Name:      "Test Client",
-					BearerKey: "test-bearer-key",
-					UpdatedAt: time.Now(),
-					CreatedAt: time.Now(),
+					Name:          "Test Client",
+					KeyPrefix:     apikey.Prefix("test-bearer-key"),
+					BearerKeyHash: apikey.Hash("test-bearer-key"),
+					UpdatedAt:     time.Now(),
+					CreatedAt:     time.Now(),
+				},
+			},
+		}
+
+		// Caller supplied keys must pass the strength rules
+		suppliedKey := "Kx7-pQ2m_Vr9sLt4Wz8nBy3cHd6fJg5a"
+		supplied := []model.Client{
+			{
+				ID: 1,
+				ClientInput: model.ClientInput{
+					Name:          "Test Client",
+					KeyPrefix:     apikey.Prefix(suppliedKey),
+					BearerKeyHash: apikey.Hash(suppliedKey),
 				},
 			},
 		}
@@ -63,57 +99,446 @@ func TestClient(t *testing.T) {
 		}
 
 		Convey("Upsert", func() {
+			mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).AnyTimes()
+
 			Convey("Input is empty", func() {
 				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{})
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Invalid inputs are rejected with field details", func() {
+				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{
+					{Name: "Test Client"},
+					{Name: "", BearerKey: "test-bearer-key", RateLimit: -1},
+				})
+				So(apperror.Code(err), ShouldEqual, apperror.Validation)
+
+				details := apperror.Details(err)
+				So(details, ShouldHaveLength, 3)
+				So(details[0].Field, ShouldEqual, "[1].name")
+				So(details[0].Rule, ShouldEqual, "required")
+				So(details[1].Field, ShouldEqual, "[1].bearer_key")
+				So(details[1].Rule, ShouldEqual, "bearer_key")
+				So(details[2].Field, ShouldEqual, "[1].rate_limit")
+			})
+
+			Convey("Name longer than the column is rejected", func() {
+				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{{Name: strings.Repeat("a", 101)}})
+				So(apperror.Details(err), ShouldResemble, []apperror.FieldError{{Field: "[0].name", Rule: "max", Message: "must be at most 100 characters"}})
+			})
+
 			Convey("Database client upsert error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Database client find by filter error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Cache client invalidate error", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), []string{outputs[0].BearerKeyHash}).Return(nil).Times(1)
 
 				results, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldBeNil)
 				So(results, ShouldNotBeEmpty)
 				So(results[0].Name, ShouldEqual, "Test Client")
 			})
+
+			Convey("Generated key is returned once and stored hashed", func() {
+				var stored model.ClientInput
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, datas []model.ClientInput) error {
+					stored = datas[0]
+					return nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.BearerKeyHashes, ShouldResemble, []string{stored.BearerKeyHash})
+					return []model.Client{{ID: 1, ClientInput: model.ClientInput{Name: stored.Name, KeyPrefix: stored.KeyPrefix, BearerKeyHash: stored.BearerKeyHash}}}, nil
+				}).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				results, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{{Name: "Test Client"}})
+				So(err, ShouldBeNil)
+				So(results[0].BearerKey, ShouldNotBeEmpty)
+				So(results[0].KeyPrefix, ShouldEqual, apikey.Prefix(results[0].BearerKey))
+				So(apikey.Verify(results[0].BearerKey, stored.BearerKeyHash), ShouldBeTrue)
+			})
+
+			Convey("Scopes are replaced only when given", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(supplied, nil).Times(1)
+				mockClientScopeDatabasePort.EXPECT().Replace(gomock.Any(), 1, []string{"clients:read", "clients:write"}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				results, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{
+					{Name: "Test Client", BearerKey: suppliedKey, Scopes: []string{"clients:write", " clients:read", "clients:write", ""}},
+				})
+				So(err, ShouldBeNil)
+				So(results[0].Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
+			Convey("Database client scope replace error rolls back the upsert", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(supplied, nil).Times(1)
+				mockClientScopeDatabasePort.EXPECT().Replace(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
+
+				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{
+					{Name: "Test Client", BearerKey: suppliedKey, Scopes: []string{}},
+				})
+				So(err, ShouldNotBeNil)
+				So(txErr, ShouldNotBeNil)
+			})
+
+			Convey("Supplied key is not echoed back", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(supplied, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				results, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{{Name: "Test Client", BearerKey: suppliedKey}})
+				So(err, ShouldBeNil)
+				So(results[0].BearerKey, ShouldBeEmpty)
+			})
 		})
 
-		Convey("FindByFilter", func() {
+		Convey("Create", func() {
+			mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).AnyTimes()
+
+			Convey("Invalid input", func() {
+				_, err := clientDomain.Client().Create(context.Background(), model.ClientInput{})
+				So(apperror.Code(err), ShouldEqual, apperror.Validation)
+			})
+
+			Convey("Taken key is a conflict", func() {
+				mockClientDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(apperror.NewConflict("bearer_key or certificate_subject is already used by another client")).Times(1)
+
+				_, err := clientDomain.Client().Create(context.Background(), model.ClientInput{Name: "Test Client", BearerKey: suppliedKey})
+				So(apperror.Code(err), ShouldEqual, apperror.Conflict)
+			})
+
+			Convey("Success returns the generated key once", func() {
+				var stored model.ClientInput
+				mockClientDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data model.ClientInput) error {
+					stored = data
+					return nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					return []model.Client{{ID: 7, ClientInput: model.ClientInput{Name: stored.Name, BearerKeyHash: stored.BearerKeyHash}}}, nil
+				}).Times(1)
+				mockClientScopeDatabasePort.EXPECT().Replace(gomock.Any(), 7, []string{"clients:read"}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().Create(context.Background(), model.ClientInput{Name: "Test Client", Scopes: []string{"clients:read"}})
+				So(err, ShouldBeNil)
+				So(result.ID, ShouldEqual, 7)
+				So(apikey.Verify(result.BearerKey, stored.BearerKeyHash), ShouldBeTrue)
+			})
+		})
+
+		Convey("Update", func() {
+			expiresAt := time.Now().Add(time.Hour)
+			current := outputs[0]
+			current.ExpiresAt = &expiresAt
+			current.RateLimit = 50
+
+			Convey("Bearer key cannot be changed", func() {
+				_, err := clientDomain.Client().Update(context.Background(), 1, model.ClientInput{Name: "Test Client", BearerKey: suppliedKey})
+				So(apperror.Details(err)[0].Field, ShouldEqual, "bearer_key")
+			})
+
+			Convey("Client not found", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(nil, nil).Times(1)
+
+				_, err := clientDomain.Client().Update(context.Background(), 1, model.ClientInput{Name: "Test Client"})
+				So(apperror.Code(err), ShouldEqual, apperror.NotFound)
+			})
+
+			Convey("Replace clears what is left out", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return([]model.Client{current}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data model.Client) error {
+					So(data.Name, ShouldEqual, "Renamed Client")
+					So(data.ExpiresAt, ShouldBeNil)
+					So(data.RateLimit, ShouldEqual, 0)
+					return nil
+				}).Times(1)
+				mockClientScopeDatabasePort.EXPECT().Replace(gomock.Any(), 1, []string{}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), current.BearerKeyHash).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().Update(context.Background(), 1, model.ClientInput{Name: "Renamed Client"})
+				So(err, ShouldBeNil)
+				So(result.Name, ShouldEqual, "Renamed Client")
+			})
+
+			Convey("Patch keeps what is left out", func() {
+				name := "Renamed Client"
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return([]model.Client{current}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data model.Client) error {
+					So(data.Name, ShouldEqual, "Renamed Client")
+					So(data.ExpiresAt, ShouldEqual, &expiresAt)
+					So(data.RateLimit, ShouldEqual, 50)
+					return nil
+				}).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), current.BearerKeyHash).Return(nil).Times(1)
+
+				_, err := clientDomain.Client().Patch(context.Background(), 1, model.ClientPatch{Name: &name})
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Patched client must stay valid", func() {
+				limit := -1
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return([]model.Client{current}, nil).Times(1)
+
+				_, err := clientDomain.Client().Patch(context.Background(), 1, model.ClientPatch{RateLimit: &limit})
+				So(apperror.Details(err)[0].Field, ShouldEqual, "rate_limit")
+			})
+		})
+
+		Convey("FindByID", func() {
+			Convey("Client not found", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(nil, nil).Times(1)
+
+				_, err := clientDomain.Client().FindByID(context.Background(), 1)
+				So(apperror.Code(err), ShouldEqual, apperror.NotFound)
+			})
+
+			Convey("Success", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(outputs, nil).Times(1)
+
+				result, err := clientDomain.Client().FindByID(context.Background(), 1)
+				So(err, ShouldBeNil)
+				So(result.Scopes, ShouldResemble, []string{"clients:read"})
+			})
+		})
+
+		Convey("DeleteByID", func() {
+			Convey("Client not found", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(nil, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := clientDomain.Client().DeleteByID(context.Background(), 1)
+				So(apperror.Code(err), ShouldEqual, apperror.NotFound)
+			})
+
+			Convey("Success invalidates the cached key", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), []string{outputs[0].BearerKeyHash}).Return(nil).Times(1)
+
+				err := clientDomain.Client().DeleteByID(context.Background(), 1)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("RotateKey", func() {
+
+			Convey("ID is empty", func() {
+				_, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Grace period is invalid", func() {
+				_, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{ID: 1, GracePeriod: "soon"})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Client not found", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(nil, nil).Times(1)
+
+				_, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{ID: 1})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Database client key insert error", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
+
+				_, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{ID: 1})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success", func() {
+				before := time.Now()
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}, true).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key model.ClientKey) error {
+					So(key.ClientID, ShouldEqual, 1)
+					So(key.BearerKeyHash, ShouldEqual, outputs[0].BearerKeyHash)
+					So(key.ExpiresAt, ShouldHappenOnOrAfter, before.Add(time.Hour))
+					So(key.ExpiresAt, ShouldHappenBefore, before.Add(2*time.Hour))
+					return nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().UpdateKey(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), outputs[0].BearerKeyHash).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{ID: 1, GracePeriod: "1h"})
+				So(err, ShouldBeNil)
+				So(result.KeyVersion, ShouldEqual, outputs[0].KeyVersion+1)
+				So(apikey.Verify(result.BearerKey, result.BearerKeyHash), ShouldBeTrue)
+				So(result.BearerKeyHash, ShouldNotEqual, outputs[0].BearerKeyHash)
+			})
+
+			Convey("Cache purge error does not undo the rotation", func() {
+				mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(inTransaction).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().UpdateKey(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
+
+				result, err := clientDomain.Client().RotateKey(context.Background(), model.ClientRotateInput{ID: 1})
+				So(err, ShouldBeNil)
+				So(result.BearerKey, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("RevokeByFilter", func() {
 			Convey("Filter is empty", func() {
-				_, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{})
+				err := clientDomain.Client().RevokeByFilter(context.Background(), model.ClientFilter{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Database client revoke by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().RevokeByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
+
+				err := clientDomain.Client().RevokeByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Success purges cached keys", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().RevokeByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), []string{outputs[0].BearerKeyHash}).Return(nil).Times(1)
+
+				err := clientDomain.Client().RevokeByFilter(context.Background(), filter)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Paging fields never narrow the purged keys", func() {
+				var found model.ClientFilter
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					found = filter
+					return outputs, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().RevokeByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := clientDomain.Client().RevokeByFilter(context.Background(), model.ClientFilter{Names: []string{"client"}, Limit: 1, Sort: model.ClientSortIDDesc, Cursor: "MQ"})
+				So(err, ShouldBeNil)
+				So(found.Limit, ShouldEqual, 0)
+				So(found.Sort, ShouldBeEmpty)
+				So(found.Cursor, ShouldBeEmpty)
+				So(found.AfterID, ShouldEqual, 0)
+			})
+		})
+
+		Convey("FindByFilter", func() {
+			Convey("Empty filter lists every client, one page at a time", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.Limit, ShouldEqual, 51)
+					return outputs, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(page, ShouldResemble, model.Page{Total: 1})
+			})
+
+			Convey("Invalid filter is rejected with field details", func() {
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{IDs: []int{1, 0}})
+				So(apperror.Details(err), ShouldResemble, []apperror.FieldError{{Field: "ids[1]", Rule: "gt", Message: "must be greater than 0"}})
+			})
+
+			Convey("Invalid paging is rejected", func() {
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{Limit: 501, Sort: "name"})
+				So(apperror.Details(err), ShouldHaveLength, 2)
+
+				_, _, err = clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{Cursor: "not-a-cursor"})
+				So(apperror.Details(err)[0].Field, ShouldEqual, "cursor")
+			})
+
 			Convey("Database client find by filter error", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
-				_, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Database client count by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error")).Times(1)
+
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				So(apperror.Code(err), ShouldEqual, apperror.Unavailable)
+			})
+
+			Convey("Bearer keys are matched by hash", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.BearerKeys, ShouldBeEmpty)
+					So(filter.BearerKeyHashes, ShouldResemble, []string{apikey.Hash("test-bearer-key")})
+					return outputs, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("A full page links to the next one", func() {
+				rows := []model.Client{{ID: 3}, {ID: 5}, {ID: 8}}
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					So(filter.Limit, ShouldEqual, 3)
+					So(filter.AfterID, ShouldEqual, 2)
+					So(filter.Sort, ShouldEqual, model.ClientSortIDDesc)
+					return rows, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(9), nil).Times(1)
+
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{
+					Search: "test", Limit: 2, Sort: model.ClientSortIDDesc, Cursor: model.EncodeCursor(2),
+				})
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 2)
+				So(page.Total, ShouldEqual, 9)
+
+				after, err := model.DecodeCursor(page.NextCursor)
+				So(err, ShouldBeNil)
+				So(after, ShouldEqual, 5)
+			})
+
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
 
-				results, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), filter)
 				So(err, ShouldBeNil)
 				So(results, ShouldNotBeEmpty)
 				So(results[0].Name, ShouldEqual, "Test Client")
+				So(page.NextCursor, ShouldBeEmpty)
 			})
 		})
 
@@ -123,18 +548,51 @@ func TestClient(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Database client find by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
+				So(err, ShouldNotBeNil)
+			})
+
 			Convey("Database client delete by filter error", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+			Convey("Cache client invalidate error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success invalidates cached keys", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), []string{outputs[0].BearerKeyHash}).Return(nil).Times(1)
+
+				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Paging fields never narrow the purged keys", func() {
+				var found model.ClientFilter
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+					found = filter
+					return outputs, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteMany(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				err := clientDomain.Client().DeleteByFilter(context.Background(), model.ClientFilter{Names: []string{"client"}, Limit: 1})
 				So(err, ShouldBeNil)
+				So(found.Limit, ShouldEqual, 0)
 			})
 		})
 
@@ -144,21 +602,40 @@ func TestClient(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Invalid input is not published", func() {
+				err := clientDomain.Client().PublishUpsert(context.Background(), []model.ClientInput{{}})
+				So(apperror.Code(err), ShouldEqual, apperror.Validation)
+			})
+
 			Convey("Message client publish upsert error", func() {
-				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().PublishUpsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Success", func() {
-				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any()).Return(nil).Times(1)
+				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				err := clientDomain.Client().PublishUpsert(context.Background(), inputs)
 				So(err, ShouldBeNil)
 			})
 		})
 
+		Convey("StartUpsert", func() {
+			Convey("Invalid input is not started", func() {
+				err := clientDomain.Client().StartUpsert(context.Background(), model.ClientInput{Name: "Test Client", BearerKey: "short"})
+				So(apperror.Details(err), ShouldHaveLength, 1)
+			})
+
+			Convey("Success", func() {
+				mockClientWorkflowPort.EXPECT().StartUpsert(gomock.Any(), inputs[0]).Return(nil).Times(1)
+
+				err := clientDomain.Client().StartUpsert(context.Background(), inputs[0])
+				So(err, ShouldBeNil)
+			})
+		})
+
 		Convey("IsExists", func() {
 			Convey("Bearer key is empty", func() {
 				_, err := clientDomain.Client().IsExists(context.Background(), "")
@@ -166,57 +643,347 @@ func TestClient(t *testing.T) {
 			})
 
 			Convey("Cache client get error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, errors.New("error")).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Database client is exists error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, errors.New("error")).Times(1)
+			Convey("Database client find by filter error", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Database client find by filter error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+			Convey("Cache client set error", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Cache client set error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
+			Convey("Prefix matches but hash does not", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), apikey.Hash("test-bearer-other"), time.Minute).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-other")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
 
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(errors.New("error")).Times(1)
+			Convey("Unknown key cache error is not fatal", func() {
+				os.Setenv("AUTH_UNKNOWN_KEY_CACHE_TTL", "5m")
+				defer os.Unsetenv("AUTH_UNKNOWN_KEY_CACHE_TTL")
+
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), gomock.Any(), 5*time.Minute).Return(errors.New("error")).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "unknown-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
+
+			Convey("Cached unknown key skips the database", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "unknown-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
+
+			Convey("Database client key find by filter error", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Superseded key within grace period", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+					So(filter.KeyPrefixes, ShouldResemble, []string{"old-bear"})
+					So(filter.ActiveAt.IsZero(), ShouldBeFalse)
+					return []model.ClientKey{{ClientID: 1, BearerKeyHash: apikey.Hash("old-bearer-key")}}, nil
+				}).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}, false).Return(outputs, nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "old-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeTrue)
+			})
+
+			Convey("Superseded key of a revoked client", func() {
+				revokedAt := time.Now().Add(-time.Minute)
+				revoked := outputs[0]
+				revoked.RevokedAt = &revokedAt
+
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{{ClientID: 1, BearerKeyHash: apikey.Hash("old-bearer-key")}}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}, false).Return([]model.Client{revoked}, nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "old-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
+
+			Convey("Expired client in database", func() {
+				expiresAt := time.Now().Add(-time.Minute)
+				expired := outputs[0]
+				expired.ExpiresAt = &expiresAt
+
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{expired}, nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
+
+			Convey("Revoked client in cache", func() {
+				revokedAt := time.Now().Add(-time.Minute)
+				revoked := outputs[0]
+				revoked.RevokedAt = &revokedAt
+
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(revoked, nil).Times(1)
+
+				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeFalse)
+			})
+
+			Convey("Last used is recorded in one batch on flush", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(outputs[0], nil).Times(2)
+				mockClientDatabasePort.EXPECT().UpdateLastUsed(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, usage map[int]time.Time) error {
+					So(usage, ShouldHaveLength, 1)
+					So(usage, ShouldContainKey, 1)
+					return nil
+				}).Times(1)
+
+				for i := 0; i < 2; i++ {
+					result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+					So(err, ShouldBeNil)
+					So(result, ShouldBeTrue)
+				}
+
+				So(clientDomain.Client().FlushUsage(context.Background()), ShouldBeNil)
+				// Nothing left to write
+				So(clientDomain.Client().FlushUsage(context.Background()), ShouldBeNil)
+			})
+
+			Convey("Failed flush keeps the batch for the next attempt", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(outputs[0], nil).Times(1)
+				gomock.InOrder(
+					mockClientDatabasePort.EXPECT().UpdateLastUsed(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1),
+					mockClientDatabasePort.EXPECT().UpdateLastUsed(gomock.Any(), gomock.Any()).Return(nil).Times(1),
+				)
+
+				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+
+				So(clientDomain.Client().FlushUsage(context.Background()), ShouldNotBeNil)
+				So(clientDomain.Client().FlushUsage(context.Background()), ShouldBeNil)
+			})
+
 			Convey("Success", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), apikey.Hash("test-bearer-key")).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{KeyPrefixes: []string{"test-bea"}}, false).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data model.Client) error {
+					// Scopes are cached along with the client
+					So(data.ID, ShouldEqual, outputs[0].ID)
+					So(data.Scopes, ShouldResemble, []string{"clients:read"})
+					return nil
+				}).Times(1)
 
 				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
 				So(err, ShouldBeNil)
 				So(result, ShouldBeTrue)
 			})
 
+			Convey("Authenticate returns the client with its scopes", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				client, exists, err := clientDomain.Client().Authenticate(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeTrue)
+				So(client.ID, ShouldEqual, 1)
+				So(client.Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
 			Convey("Cache client exists", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(outputs[0], nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(outputs[0], nil).Times(1)
 
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				So(err, ShouldBeNil)
+				So(result, ShouldBeTrue)
+			})
+		})
+
+		Convey("AuthenticateCertificate", func() {
+			identities := []string{"spiffe://example.org/billing", "billing"}
+
+			Convey("Identities are empty", func() {
+				_, _, err := clientDomain.Client().AuthenticateCertificate(context.Background(), nil)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Database client find by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				_, _, err := clientDomain.Client().AuthenticateCertificate(context.Background(), identities)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("No client for the certificate", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{CertificateSubjects: identities}, false).Return(nil, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().AuthenticateCertificate(context.Background(), identities)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Most specific identity wins", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ID: 2, ClientInput: model.ClientInput{Name: "By Name", CertificateSubject: "billing"}},
+					{ID: 1, ClientInput: model.ClientInput{Name: "By SPIFFE ID", CertificateSubject: "spiffe://example.org/billing"}},
+				}, nil).Times(1)
+
+				client, exists, err := clientDomain.Client().AuthenticateCertificate(context.Background(), identities)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeTrue)
+				So(client.ID, ShouldEqual, 1)
+				So(client.Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
+			Convey("Revoked client", func() {
+				revokedAt := time.Now().Add(-time.Minute)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{
+					{ID: 1, RevokedAt: &revokedAt, ClientInput: model.ClientInput{CertificateSubject: "billing"}},
+				}, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().AuthenticateCertificate(context.Background(), identities)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+		})
+
+		Convey("IssueSigningSecret", func() {
+			os.Setenv("SECRET_ENCRYPTION_KEY", "test-encryption-key")
+			defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+
+			Convey("Client not found", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+				_, err := clientDomain.Client().IssueSigningSecret(context.Background(), model.ClientSigningSecretInput{ID: 1})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Secret is stored sealed and returned once", func() {
+				var stored model.Client
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().UpdateSigningSecret(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, data model.Client) error {
+						stored = data
+						return nil
+					}).Times(1)
+
+				result, err := clientDomain.Client().IssueSigningSecret(context.Background(), model.ClientSigningSecretInput{ID: 1})
+				So(err, ShouldBeNil)
+				So(result.SigningSecret, ShouldNotBeEmpty)
+				So(result.SealedSigningSecret, ShouldBeEmpty)
+				So(stored.SealedSigningSecret, ShouldNotContainSubstring, result.SigningSecret)
+
+				opened, err := secretbox.Open(stored.SealedSigningSecret)
+				So(err, ShouldBeNil)
+				So(opened, ShouldEqual, result.SigningSecret)
+			})
+		})
+
+		Convey("VerifySignature", func() {
+			os.Setenv("SECRET_ENCRYPTION_KEY", "test-encryption-key")
+			defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+
+			sealed, _ := secretbox.Seal("signing-secret")
+			signer := model.Client{ID: 1, SealedSigningSecret: sealed, ClientInput: model.ClientInput{Name: "Test Client"}}
+			body := []byte(`{"name":"Test Client"}`)
+			signed := func(timestamp time.Time, nonce string) model.SignedRequest {
+				ts := strconv.FormatInt(timestamp.Unix(), 10)
+				return model.SignedRequest{
+					ClientID:   "1",
+					Method:     "POST",
+					RequestURI: "/v1/ping",
+					Timestamp:  ts,
+					Nonce:      nonce,
+					Signature:  signature.Sign("signing-secret", "POST", "/v1/ping", ts, nonce, body),
+					Body:       body,
+				}
+			}
+
+			Convey("Valid signature", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), "1:nonce-1", 10*time.Minute).Return(true, nil).Times(1)
+
+				client, exists, err := clientDomain.Client().VerifySignature(context.Background(), signed(time.Now(), "nonce-1"))
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeTrue)
+				So(client.ID, ShouldEqual, 1)
+				So(client.SealedSigningSecret, ShouldBeEmpty)
+				So(client.Scopes, ShouldResemble, []string{"clients:read"})
+			})
+
+			Convey("Replayed nonce", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().VerifySignature(context.Background(), signed(time.Now(), "nonce-1"))
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Stale timestamp", func() {
+				_, exists, err := clientDomain.Client().VerifySignature(context.Background(), signed(time.Now().Add(-10*time.Minute), "nonce-1"))
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Tampered body", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+
+				request := signed(time.Now(), "nonce-1")
+				request.Body = []byte(`{"name":"Other"}`)
+				_, exists, err := clientDomain.Client().VerifySignature(context.Background(), request)
 				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Client without a signing secret", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().VerifySignature(context.Background(), signed(time.Now(), "nonce-1"))
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Nonce cache error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{signer}, nil).Times(1)
+				mockNonceCachePort.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errors.New("error")).Times(1)
+
+				_, _, err := clientDomain.Client().VerifySignature(context.Background(), signed(time.Now(), "nonce-1"))
+				So(err, ShouldNotBeNil)
 			})
 		})
 	})
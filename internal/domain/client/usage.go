@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils"
+	"go-template/utils/activity"
+	"go-template/utils/log"
+)
+
+const defaultUsageFlushInterval = 30 * time.Second
+
+// usageRecorder buffers last used timestamps in memory so authentication never
+// waits on a write; the buffer is flushed to the database in one batch
+type usageRecorder struct {
+	databasePort outbound_port.DatabasePort
+	interval     time.Duration
+
+	mu      sync.Mutex
+	pending map[int]time.Time
+	start   sync.Once
+}
+
+func newUsageRecorder(databasePort outbound_port.DatabasePort) *usageRecorder {
+	return &usageRecorder{
+		databasePort: databasePort,
+		interval:     usageFlushInterval(),
+		pending:      make(map[int]time.Time),
+	}
+}
+
+// Record notes that a client was used, starting the background flush on first use
+func (r *usageRecorder) Record(id int, at time.Time) {
+	r.mu.Lock()
+	if previous, ok := r.pending[id]; !ok || at.After(previous) {
+		r.pending[id] = at
+	}
+	r.mu.Unlock()
+
+	r.start.Do(func() {
+		go r.run()
+	})
+}
+
+// Flush writes every buffered timestamp, putting them back if the write fails
+func (r *usageRecorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = make(map[int]time.Time)
+	r.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := r.databasePort.Client().UpdateLastUsed(ctx, batch)
+	if err != nil {
+		r.mu.Lock()
+		for id, at := range batch {
+			if previous, ok := r.pending[id]; !ok || at.After(previous) {
+				r.pending[id] = at
+			}
+		}
+		r.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (r *usageRecorder) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := activity.NewContext("client_last_used_flush")
+		if err := r.Flush(ctx); err != nil {
+			log.WithContext(ctx).Error("flush client last used error", err)
+		}
+	}
+}
+
+// usageFlushInterval reads CLIENT_LAST_USED_FLUSH_INTERVAL, defaulting to 30 seconds
+func usageFlushInterval() time.Duration {
+	return utils.GetEnvDuration("CLIENT_LAST_USED_FLUSH_INTERVAL", defaultUsageFlushInterval)
+}
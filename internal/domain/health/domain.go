@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils/log"
+)
+
+const (
+	// readinessCacheTTL keeps orchestrator polling from hammering every dependency
+	readinessCacheTTL     = 2 * time.Second
+	readinessCheckTimeout = 2 * time.Second
+
+	// Readiness is unauthenticated, so failures are reported with these
+	// instead of driver errors that may name hosts, ports or DSNs
+	checkErrorTimeout     = "timeout"
+	checkErrorUnavailable = "unavailable"
+)
+
+type HealthDomain interface {
+	Liveness(ctx context.Context) model.Health
+	Readiness(ctx context.Context) model.Health
+}
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type healthDomain struct {
+	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
+	cachePort    outbound_port.CachePort
+	workflowPort outbound_port.WorkflowPort
+
+	mu        sync.Mutex
+	cached    model.Health
+	expiresAt time.Time
+}
+
+func NewHealthDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	cachePort outbound_port.CachePort,
+	workflowPort outbound_port.WorkflowPort,
+) HealthDomain {
+	return &healthDomain{
+		databasePort: databasePort,
+		messagePort:  messagePort,
+		cachePort:    cachePort,
+		workflowPort: workflowPort,
+	}
+}
+
+func (s *healthDomain) Liveness(ctx context.Context) model.Health {
+	return model.Health{
+		Status:    model.HealthStatusUp,
+		CheckedAt: time.Now(),
+	}
+}
+
+func (s *healthDomain) Readiness(ctx context.Context) model.Health {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Now().Before(s.expiresAt) {
+		return s.cached
+	}
+
+	s.cached = s.probe(ctx)
+	s.expiresAt = time.Now().Add(readinessCacheTTL)
+	return s.cached
+}
+
+func (s *healthDomain) probe(ctx context.Context) model.Health {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	targets := map[string]pinger{}
+	if s.databasePort != nil {
+		targets["database"] = s.databasePort
+	}
+	if s.cachePort != nil {
+		targets["cache"] = s.cachePort
+	}
+	if s.messagePort != nil {
+		targets["message"] = s.messagePort
+	}
+	if s.workflowPort != nil {
+		targets["workflow"] = s.workflowPort
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	result := model.Health{
+		Status:    model.HealthStatusUp,
+		Checks:    make(map[string]model.HealthCheck, len(targets)),
+		CheckedAt: time.Now(),
+	}
+	for name, target := range targets {
+		wg.Add(1)
+		go func(name string, target pinger) {
+			defer wg.Done()
+			check := ping(ctx, name, target)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = check
+			if check.Status != model.HealthStatusUp {
+				result.Status = model.HealthStatusDown
+			}
+		}(name, target)
+	}
+	wg.Wait()
+
+	return result
+}
+
+func ping(ctx context.Context, name string, target pinger) model.HealthCheck {
+	start := time.Now()
+	err := target.Ping(ctx)
+	check := model.HealthCheck{
+		Status:    model.HealthStatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		log.WithContext(ctx).Error(name+" readiness check error", err)
+		check.Status = model.HealthStatusDown
+		check.Error = checkErrorUnavailable
+		if errors.Is(err, context.DeadlineExceeded) {
+			check.Error = checkErrorTimeout
+		}
+	}
+	return check
+}
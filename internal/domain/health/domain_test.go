@@ -0,0 +1,85 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	mock_outbound_port "go-template/tests/mocks/port"
+)
+
+func TestHealth(t *testing.T) {
+	Convey("Test Health", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+
+		healthDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+
+		Convey("Liveness", func() {
+			result := healthDomain.Health().Liveness(context.Background())
+			So(result.Status, ShouldEqual, model.HealthStatusUp)
+			So(result.Checks, ShouldBeEmpty)
+		})
+
+		Convey("Readiness", func() {
+			Convey("All dependencies up", func() {
+				mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+				result := healthDomain.Health().Readiness(context.Background())
+				So(result.IsUp(), ShouldBeTrue)
+				So(result.Checks, ShouldHaveLength, 4)
+				So(result.Checks["database"].Status, ShouldEqual, model.HealthStatusUp)
+			})
+
+			Convey("One dependency down", func() {
+				mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(errors.New("dial tcp 10.0.0.5:6379: connection refused")).Times(1)
+				mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+				result := healthDomain.Health().Readiness(context.Background())
+				So(result.IsUp(), ShouldBeFalse)
+				So(result.Checks["cache"].Status, ShouldEqual, model.HealthStatusDown)
+				// Driver errors stay in the logs, the unauthenticated response is generic
+				So(result.Checks["cache"].Error, ShouldEqual, "unavailable")
+				So(result.Checks["database"].Status, ShouldEqual, model.HealthStatusUp)
+			})
+
+			Convey("Timed out dependency", func() {
+				mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(fmt.Errorf("ping postgres: %w", context.DeadlineExceeded)).Times(1)
+				mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+				result := healthDomain.Health().Readiness(context.Background())
+				So(result.Checks["database"].Error, ShouldEqual, "timeout")
+			})
+
+			Convey("Result is cached", func() {
+				mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+				mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+				first := healthDomain.Health().Readiness(context.Background())
+				second := healthDomain.Health().Readiness(context.Background())
+				So(second.CheckedAt, ShouldEqual, first.CheckedAt)
+			})
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils"
+	"go-template/utils/activity"
+	"go-template/utils/log"
+)
+
+const (
+	defaultThreshold = 10
+	defaultWindow    = 15 * time.Minute
+	defaultDuration  = time.Minute
+	defaultMax       = time.Hour
+	// strikeMemory is how long a subject must stay quiet before its next
+	// lockout starts again from the base duration
+	strikeMemory = 24 * time.Hour
+)
+
+type LockoutDomain interface {
+	LockedFor(ctx context.Context, scope string, subject string) time.Duration
+	Fail(ctx context.Context, scope string, subject string) time.Duration
+	Succeed(ctx context.Context, scope string, subject string)
+}
+
+type lockoutDomain struct {
+	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
+	cachePort    outbound_port.CachePort
+	workflowPort outbound_port.WorkflowPort
+}
+
+func NewLockoutDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	cachePort outbound_port.CachePort,
+	workflowPort outbound_port.WorkflowPort,
+) LockoutDomain {
+	return &lockoutDomain{
+		databasePort: databasePort,
+		messagePort:  messagePort,
+		cachePort:    cachePort,
+		workflowPort: workflowPort,
+	}
+}
+
+// LockedFor reports how long subject stays locked out of scope. Cache errors
+// are logged and let the attempt through rather than locking everyone out
+func (s *lockoutDomain) LockedFor(ctx context.Context, scope string, subject string) time.Duration {
+	if utils.GetEnvInt("AUTH_LOCKOUT_THRESHOLD", defaultThreshold) <= 0 {
+		return 0
+	}
+
+	locked, err := s.cachePort.Lockout().LockedFor(ctx, scope+":"+subject)
+	if err != nil {
+		log.WithContext(ctx).Error("get lockout from cache error", err)
+		return 0
+	}
+	return locked
+}
+
+// Fail counts a failed attempt. Reaching AUTH_LOCKOUT_THRESHOLD within
+// AUTH_LOCKOUT_WINDOW locks subject out, for AUTH_LOCKOUT_DURATION doubled
+// with every lockout in a row up to AUTH_LOCKOUT_MAX, and returns how long
+func (s *lockoutDomain) Fail(ctx context.Context, scope string, subject string) time.Duration {
+	threshold := utils.GetEnvInt("AUTH_LOCKOUT_THRESHOLD", defaultThreshold)
+	if threshold <= 0 {
+		return 0
+	}
+
+	key := scope + ":" + subject
+	cacheLockoutPort := s.cachePort.Lockout()
+	failures, err := cacheLockoutPort.AddFailure(ctx, key, utils.GetEnvDuration("AUTH_LOCKOUT_WINDOW", defaultWindow))
+	if err != nil {
+		log.WithContext(ctx).Error("add lockout failure to cache error", err)
+		return 0
+	}
+	if failures < int64(threshold) {
+		return 0
+	}
+
+	strikes, err := cacheLockoutPort.AddStrike(ctx, key, strikeMemory)
+	if err != nil {
+		log.WithContext(ctx).Error("add lockout strike to cache error", err)
+		strikes = 1
+	}
+
+	duration := lockoutDuration(strikes,
+		utils.GetEnvDuration("AUTH_LOCKOUT_DURATION", defaultDuration),
+		utils.GetEnvDuration("AUTH_LOCKOUT_MAX", defaultMax),
+	)
+	err = cacheLockoutPort.Lock(ctx, key, duration)
+	if err != nil {
+		log.WithContext(ctx).Error("set lockout to cache error", err)
+		return 0
+	}
+
+	log.WithContext(activity.WithResult(ctx, model.Lockout{
+		Scope:    scope,
+		Subject:  subject,
+		Failures: failures,
+		Strikes:  strikes,
+		Duration: duration,
+		Until:    time.Now().Add(duration),
+	})).Warn("authentication lockout")
+
+	return duration
+}
+
+// Succeed forgets the failed attempts of subject. Strikes remain, so a
+// subject alternating guesses with a valid key still escalates
+func (s *lockoutDomain) Succeed(ctx context.Context, scope string, subject string) {
+	if utils.GetEnvInt("AUTH_LOCKOUT_THRESHOLD", defaultThreshold) <= 0 {
+		return
+	}
+
+	err := s.cachePort.Lockout().ResetFailures(ctx, scope+":"+subject)
+	if err != nil {
+		log.WithContext(ctx).Error("reset lockout failures in cache error", err)
+	}
+}
+
+// lockoutDuration doubles base for every strike after the first, capped at max
+func lockoutDuration(strikes int64, base, max time.Duration) time.Duration {
+	duration := base
+	for i := int64(1); i < strikes && duration < max; i++ {
+		duration *= 2
+	}
+	if duration > max {
+		return max
+	}
+	return duration
+}
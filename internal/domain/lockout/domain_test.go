@@ -0,0 +1,99 @@
+package lockout_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	mock_outbound_port "go-template/tests/mocks/port"
+)
+
+func TestLockout(t *testing.T) {
+	Convey("Test Lockout", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+		mockLockoutCachePort := mock_outbound_port.NewMockLockoutCachePort(mockCtrl)
+
+		mockCachePort.EXPECT().Lockout().Return(mockLockoutCachePort).AnyTimes()
+
+		lockoutDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort).Lockout()
+		ctx := context.Background()
+		key := model.LockoutScopeClient + ":192.0.2.1"
+
+		Convey("LockedFor", func() {
+			Convey("Reports the remaining lockout", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), key).Return(time.Minute, nil).Times(1)
+
+				So(lockoutDomain.LockedFor(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, time.Minute)
+			})
+
+			Convey("Cache error lets the attempt through", func() {
+				mockLockoutCachePort.EXPECT().LockedFor(gomock.Any(), key).Return(time.Duration(0), errors.New("error")).Times(1)
+
+				So(lockoutDomain.LockedFor(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 0)
+			})
+
+			Convey("Disabled with a zero threshold", func() {
+				os.Setenv("AUTH_LOCKOUT_THRESHOLD", "0")
+				defer os.Unsetenv("AUTH_LOCKOUT_THRESHOLD")
+
+				So(lockoutDomain.LockedFor(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Fail", func() {
+			Convey("Below the threshold", func() {
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), key, 15*time.Minute).Return(int64(9), nil).Times(1)
+
+				So(lockoutDomain.Fail(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 0)
+			})
+
+			Convey("First lockout uses the base duration", func() {
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), key, gomock.Any()).Return(int64(10), nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddStrike(gomock.Any(), key, 24*time.Hour).Return(int64(1), nil).Times(1)
+				mockLockoutCachePort.EXPECT().Lock(gomock.Any(), key, time.Minute).Return(nil).Times(1)
+
+				So(lockoutDomain.Fail(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, time.Minute)
+			})
+
+			Convey("Repeated lockouts double up to the maximum", func() {
+				os.Setenv("AUTH_LOCKOUT_MAX", "5m")
+				defer os.Unsetenv("AUTH_LOCKOUT_MAX")
+
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), key, gomock.Any()).Return(int64(10), nil).Times(2)
+				mockLockoutCachePort.EXPECT().AddStrike(gomock.Any(), key, gomock.Any()).Return(int64(3), nil).Times(1)
+				mockLockoutCachePort.EXPECT().Lock(gomock.Any(), key, 4*time.Minute).Return(nil).Times(1)
+				mockLockoutCachePort.EXPECT().AddStrike(gomock.Any(), key, gomock.Any()).Return(int64(4), nil).Times(1)
+				mockLockoutCachePort.EXPECT().Lock(gomock.Any(), key, 5*time.Minute).Return(nil).Times(1)
+
+				So(lockoutDomain.Fail(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 4*time.Minute)
+				So(lockoutDomain.Fail(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 5*time.Minute)
+			})
+
+			Convey("Cache error does not lock out", func() {
+				mockLockoutCachePort.EXPECT().AddFailure(gomock.Any(), key, gomock.Any()).Return(int64(0), errors.New("error")).Times(1)
+
+				So(lockoutDomain.Fail(ctx, model.LockoutScopeClient, "192.0.2.1"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Succeed resets the failures", func() {
+			mockLockoutCachePort.EXPECT().ResetFailures(gomock.Any(), key).Return(nil).Times(1)
+
+			lockoutDomain.Succeed(ctx, model.LockoutScopeClient, "192.0.2.1")
+		})
+	})
+}
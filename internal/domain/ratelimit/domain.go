@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils"
+	"go-template/utils/log"
+	"go-template/utils/ratelimit"
+)
+
+const (
+	defaultLimit  = 600
+	defaultWindow = time.Minute
+)
+
+type RateLimitDomain interface {
+	Allow(ctx context.Context, key string, limit int) model.RateLimitResult
+}
+
+type rateLimitDomain struct {
+	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
+	cachePort    outbound_port.CachePort
+	workflowPort outbound_port.WorkflowPort
+	memory       *ratelimit.Memory
+}
+
+func NewRateLimitDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	cachePort outbound_port.CachePort,
+	workflowPort outbound_port.WorkflowPort,
+) RateLimitDomain {
+	return &rateLimitDomain{
+		databasePort: databasePort,
+		messagePort:  messagePort,
+		cachePort:    cachePort,
+		workflowPort: workflowPort,
+		memory:       ratelimit.NewMemory(),
+	}
+}
+
+// Allow counts one request for key. A limit of 0 uses RATE_LIMIT_DEFAULT.
+// Counters live in the cache unless RATE_LIMIT_STORE=memory. When the cache
+// fails, this instance limits on its own instead of rejecting every request
+func (s *rateLimitDomain) Allow(ctx context.Context, key string, limit int) model.RateLimitResult {
+	if limit <= 0 {
+		limit = utils.GetEnvInt("RATE_LIMIT_DEFAULT", defaultLimit)
+	}
+	if limit <= 0 {
+		return model.RateLimitResult{Allowed: true}
+	}
+	window := utils.GetEnvDuration("RATE_LIMIT_WINDOW", defaultWindow)
+
+	if os.Getenv("RATE_LIMIT_STORE") != "memory" && s.cachePort != nil {
+		result, err := s.cachePort.RateLimit().Allow(ctx, key, limit, window)
+		if err == nil {
+			return result
+		}
+		log.WithContext(ctx).Error("rate limit cache error, falling back to memory", err)
+	}
+
+	return model.RateLimitResult(s.memory.Allow(key, limit, window, time.Now()))
+}
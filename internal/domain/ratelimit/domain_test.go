@@ -0,0 +1,83 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	mock_outbound_port "go-template/tests/mocks/port"
+)
+
+func TestRateLimit(t *testing.T) {
+	Convey("Test Rate Limit", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+		mockRateLimitCachePort := mock_outbound_port.NewMockRateLimitCachePort(mockCtrl)
+
+		mockCachePort.EXPECT().RateLimit().Return(mockRateLimitCachePort).AnyTimes()
+
+		rateLimitDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort).RateLimit()
+		ctx := context.Background()
+
+		Convey("Counts in the cache with the client limit", func() {
+			mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), "client:1", 5, time.Minute).
+				Return(model.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4}, nil).Times(1)
+
+			result := rateLimitDomain.Allow(ctx, "client:1", 5)
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Remaining, ShouldEqual, 4)
+		})
+
+		Convey("Falls back to the default limit and window", func() {
+			os.Setenv("RATE_LIMIT_DEFAULT", "10")
+			os.Setenv("RATE_LIMIT_WINDOW", "1s")
+			defer os.Unsetenv("RATE_LIMIT_DEFAULT")
+			defer os.Unsetenv("RATE_LIMIT_WINDOW")
+
+			mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), "ip:192.0.2.1", 10, time.Second).
+				Return(model.RateLimitResult{Allowed: true, Limit: 10}, nil).Times(1)
+
+			So(rateLimitDomain.Allow(ctx, "ip:192.0.2.1", 0).Limit, ShouldEqual, 10)
+		})
+
+		Convey("Limits in memory when the cache fails", func() {
+			mockRateLimitCachePort.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(model.RateLimitResult{}, errors.New("connection refused")).Times(2)
+
+			So(rateLimitDomain.Allow(ctx, "client:1", 1).Allowed, ShouldBeTrue)
+			So(rateLimitDomain.Allow(ctx, "client:1", 1).Allowed, ShouldBeFalse)
+		})
+
+		Convey("Memory store never touches the cache", func() {
+			os.Setenv("RATE_LIMIT_STORE", "memory")
+			defer os.Unsetenv("RATE_LIMIT_STORE")
+
+			result := rateLimitDomain.Allow(ctx, "client:1", 2)
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Limit, ShouldEqual, 2)
+			So(result.Remaining, ShouldEqual, 1)
+		})
+
+		Convey("Disabled when the default limit is zero", func() {
+			os.Setenv("RATE_LIMIT_DEFAULT", "0")
+			defer os.Unsetenv("RATE_LIMIT_DEFAULT")
+
+			result := rateLimitDomain.Allow(ctx, "ip:192.0.2.1", 0)
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Limit, ShouldEqual, 0)
+		})
+	})
+}
@@ -1,12 +1,22 @@
 package domain
 
 import (
+	"go-template/internal/domain/auth"
 	"go-template/internal/domain/client"
+	"go-template/internal/domain/health"
+	"go-template/internal/domain/lockout"
+	"go-template/internal/domain/ratelimit"
+	"go-template/internal/domain/token"
 	outbound_port "go-template/internal/port/outbound"
 )
 
 type Domain interface {
 	Client() client.ClientDomain
+	Health() health.HealthDomain
+	Token() token.TokenDomain
+	RateLimit() ratelimit.RateLimitDomain
+	Lockout() lockout.LockoutDomain
+	Auth() auth.AuthDomain
 }
 
 type domain struct {
@@ -14,6 +24,12 @@ type domain struct {
 	messagePort  outbound_port.MessagePort
 	cachePort    outbound_port.CachePort
 	workflowPort outbound_port.WorkflowPort
+	client       client.ClientDomain
+	health       health.HealthDomain
+	token        token.TokenDomain
+	rateLimit    ratelimit.RateLimitDomain
+	lockout      lockout.LockoutDomain
+	auth         auth.AuthDomain
 }
 
 func NewDomain(
@@ -22,14 +38,47 @@ func NewDomain(
 	cachePort outbound_port.CachePort,
 	workflowPort outbound_port.WorkflowPort,
 ) Domain {
-	return &domain{
+	d := &domain{
 		databasePort: databasePort,
 		messagePort:  messagePort,
 		cachePort:    cachePort,
 		workflowPort: workflowPort,
+		client:       client.NewClientDomain(databasePort, messagePort, cachePort, workflowPort),
+		health:       health.NewHealthDomain(databasePort, messagePort, cachePort, workflowPort),
+		token:        token.NewTokenDomain(databasePort, messagePort, cachePort, workflowPort),
+		rateLimit:    ratelimit.NewRateLimitDomain(databasePort, messagePort, cachePort, workflowPort),
+		lockout:      lockout.NewLockoutDomain(databasePort, messagePort, cachePort, workflowPort),
 	}
+	d.auth = auth.NewAuthDomain(d.client, d.token, d.lockout)
+	return d
 }
 
+// Client is shared across calls so last used timestamps are batched in one place
 func (d *domain) Client() client.ClientDomain {
-	return client.NewClientDomain(d.databasePort, d.messagePort, d.cachePort, d.workflowPort)
+	return d.client
+}
+
+// Health is shared across calls so readiness results can be cached
+func (d *domain) Health() health.HealthDomain {
+	return d.health
+}
+
+// Token resolves opaque access tokens for the introspection auth driver
+func (d *domain) Token() token.TokenDomain {
+	return d.token
+}
+
+// RateLimit is shared across calls so the in-memory fallback counts every request
+func (d *domain) RateLimit() ratelimit.RateLimitDomain {
+	return d.rateLimit
+}
+
+// Lockout tracks failed authentication attempts per caller
+func (d *domain) Lockout() lockout.LockoutDomain {
+	return d.lockout
+}
+
+// Auth resolves callers into principals the same way for every transport
+func (d *domain) Auth() auth.AuthDomain {
+	return d.auth
 }
@@ -0,0 +1,111 @@
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+
+	"go-template/internal/model"
+	outbound_port "go-template/internal/port/outbound"
+	"go-template/utils"
+	"go-template/utils/apperror"
+	"go-template/utils/introspection"
+)
+
+// defaultCacheTTL applies to inactive tokens and to active ones without `exp`
+const defaultCacheTTL = time.Minute
+
+type TokenDomain interface {
+	Introspect(ctx context.Context, token string) (model.Principal, bool, error)
+}
+
+type tokenDomain struct {
+	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
+	cachePort    outbound_port.CachePort
+	workflowPort outbound_port.WorkflowPort
+}
+
+func NewTokenDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	cachePort outbound_port.CachePort,
+	workflowPort outbound_port.WorkflowPort,
+) TokenDomain {
+	return &tokenDomain{
+		databasePort: databasePort,
+		messagePort:  messagePort,
+		cachePort:    cachePort,
+		workflowPort: workflowPort,
+	}
+}
+
+// Introspect resolves an opaque access token through the AUTH_INTROSPECTION_URL
+// endpoint. Both active and inactive results are cached, keyed by the token hash
+func (s *tokenDomain) Introspect(ctx context.Context, token string) (model.Principal, bool, error) {
+	if token == "" {
+		return model.Principal{}, false, apperror.NewValidation("token is empty")
+	}
+
+	now := time.Now()
+	tokenHash := hashToken(token)
+	cacheTokenPort := s.cachePort.Token()
+	cached, err := cacheTokenPort.Get(ctx, tokenHash)
+	if err == nil {
+		return cached.Principal, cached.Active && now.Before(cached.ExpiresAt), nil
+	}
+	if err != redis.Nil {
+		return model.Principal{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "get token from cache error")
+	}
+
+	client := introspection.SharedClient(
+		os.Getenv("AUTH_INTROSPECTION_URL"),
+		os.Getenv("AUTH_INTROSPECTION_CLIENT_ID"),
+		os.Getenv("AUTH_INTROSPECTION_CLIENT_SECRET"),
+	)
+	result, err := client.Introspect(ctx, token)
+	if err != nil {
+		return model.Principal{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "introspect token error")
+	}
+
+	data := model.TokenIntrospection{ExpiresAt: now.Add(cacheTTL())}
+	if expiry := result.Expiry(); result.Active && !expiry.IsZero() {
+		data.ExpiresAt = expiry
+	}
+	if result.Active && now.Before(data.ExpiresAt) {
+		data.Active = true
+		data.Principal = model.Principal{
+			Subject: result.Principal(),
+			Source:  model.PrincipalSourceIntrospection,
+			Scopes:  result.Scopes(),
+			Issuer:  result.Issuer,
+			Claims:  result.Claims,
+		}
+	}
+
+	// An active token is cached for its remaining lifetime, so it is never served past `exp`
+	if ttl := data.ExpiresAt.Sub(now); ttl > 0 {
+		err = cacheTokenPort.Set(ctx, tokenHash, data, ttl)
+		if err != nil {
+			return model.Principal{}, false, stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "set token to cache error")
+		}
+	}
+
+	return data.Principal, data.Active, nil
+}
+
+// hashToken keeps raw tokens out of the cache
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheTTL reads AUTH_INTROSPECTION_CACHE_TTL, falling back to one minute
+func cacheTTL() time.Duration {
+	return utils.GetEnvDuration("AUTH_INTROSPECTION_CACHE_TTL", defaultCacheTTL)
+}
@@ -0,0 +1,144 @@
+package token_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/internal/domain"
+	"go-template/internal/model"
+	mock_outbound_port "go-template/tests/mocks/port"
+)
+
+func TestToken(t *testing.T) {
+	Convey("Test Token", t, func() {
+		mockCtrl := gomock.NewController(t)
+
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+		mockTokenCachePort := mock_outbound_port.NewMockTokenCachePort(mockCtrl)
+
+		mockCachePort.EXPECT().Token().Return(mockTokenCachePort).AnyTimes()
+
+		exp := time.Now().Add(10 * time.Minute).Unix()
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			switch r.FormValue("token") {
+			case "active-token":
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"active": true,
+					"sub":    "service-account",
+					"scope":  "clients:read",
+					"iss":    "https://idp.example.com/",
+					"exp":    exp,
+				})
+			case "broken-token":
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+			}
+		}))
+		defer server.Close()
+
+		t.Setenv("AUTH_INTROSPECTION_URL", server.URL)
+		t.Setenv("AUTH_INTROSPECTION_CLIENT_ID", "go-template")
+		t.Setenv("AUTH_INTROSPECTION_CLIENT_SECRET", "s3cret")
+		t.Setenv("AUTH_INTROSPECTION_CACHE_TTL", "")
+
+		tokenDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort).Token()
+		ctx := context.Background()
+
+		Convey("Empty token", func() {
+			_, _, err := tokenDomain.Introspect(ctx, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Active token is introspected and cached for its remaining lifetime", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{}, redis.Nil).Times(1)
+			mockTokenCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tokenHash string, data model.TokenIntrospection, ttl time.Duration) error {
+					So(tokenHash, ShouldNotContainSubstring, "active-token")
+					So(data.Active, ShouldBeTrue)
+					So(data.ExpiresAt.Unix(), ShouldEqual, exp)
+					So(ttl, ShouldBeBetweenOrEqual, 9*time.Minute, 10*time.Minute)
+					return nil
+				}).Times(1)
+
+			principal, active, err := tokenDomain.Introspect(ctx, "active-token")
+			So(err, ShouldBeNil)
+			So(active, ShouldBeTrue)
+			So(principal.Subject, ShouldEqual, "service-account")
+			So(principal.Source, ShouldEqual, model.PrincipalSourceIntrospection)
+			So(principal.Scopes, ShouldResemble, []string{"clients:read"})
+			So(principal.Issuer, ShouldEqual, "https://idp.example.com/")
+		})
+
+		Convey("Inactive token is cached for the default TTL", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{}, redis.Nil).Times(1)
+			mockTokenCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), time.Minute).DoAndReturn(
+				func(ctx context.Context, tokenHash string, data model.TokenIntrospection, ttl time.Duration) error {
+					So(data.Active, ShouldBeFalse)
+					return nil
+				}).Times(1)
+
+			_, active, err := tokenDomain.Introspect(ctx, "revoked-token")
+			So(err, ShouldBeNil)
+			So(active, ShouldBeFalse)
+		})
+
+		Convey("Cached result skips the endpoint", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{
+				Active:    true,
+				Principal: model.Principal{Subject: "service-account"},
+				ExpiresAt: time.Now().Add(time.Minute),
+			}, nil).Times(1)
+
+			principal, active, err := tokenDomain.Introspect(ctx, "active-token")
+			So(err, ShouldBeNil)
+			So(active, ShouldBeTrue)
+			So(principal.Subject, ShouldEqual, "service-account")
+			So(atomic.LoadInt32(&requests), ShouldEqual, 0)
+		})
+
+		Convey("Cached inactive result", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{
+				ExpiresAt: time.Now().Add(time.Minute),
+			}, nil).Times(1)
+
+			_, active, err := tokenDomain.Introspect(ctx, "revoked-token")
+			So(err, ShouldBeNil)
+			So(active, ShouldBeFalse)
+			So(atomic.LoadInt32(&requests), ShouldEqual, 0)
+		})
+
+		Convey("Endpoint error", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{}, redis.Nil).Times(1)
+
+			_, active, err := tokenDomain.Introspect(ctx, "broken-token")
+			So(err, ShouldNotBeNil)
+			So(active, ShouldBeFalse)
+		})
+
+		Convey("Cache error", func() {
+			mockTokenCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.TokenIntrospection{}, errors.New("connection refused")).Times(1)
+
+			_, _, err := tokenDomain.Introspect(ctx, "active-token")
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&requests), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pressly/goose/v3"
+
+	"go-template/utils/apikey"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientBearerKeyHash, downClientBearerKeyHash)
+}
+
+func upClientBearerKeyHash(ctx context.Context, tx *sql.Tx) error {
+	// Bearer keys are stored as a peppered hash plus a short public prefix for lookup
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		ADD COLUMN IF NOT EXISTS key_prefix VARCHAR(16),
+		ADD COLUMN IF NOT EXISTS bearer_key_hash VARCHAR(64);`)
+	if err != nil {
+		return err
+	}
+
+	// Hash the plaintext keys of existing rows before the column is dropped
+	rows, err := tx.QueryContext(ctx, `SELECT id, bearer_key FROM clients WHERE bearer_key IS NOT NULL;`)
+	if err != nil {
+		return err
+	}
+	keys := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var key string
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return err
+		}
+		keys[id] = key
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	// The plaintext column is dropped below, so hashing under an empty pepper
+	// could never be repaired. Empty tables need no pepper
+	if len(keys) > 0 {
+		if err := apikey.RequirePepper(); err != nil {
+			return err
+		}
+	}
+
+	for id, key := range keys {
+		_, err = tx.ExecContext(ctx, `UPDATE clients SET key_prefix = $1, bearer_key_hash = $2 WHERE id = $3;`,
+			apikey.Prefix(key), apikey.Hash(key), id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `ALTER TABLE clients DROP COLUMN IF EXISTS bearer_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS clients_bearer_key_hash_key ON clients (bearer_key_hash);
+		CREATE INDEX IF NOT EXISTS clients_key_prefix_idx ON clients (key_prefix);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientBearerKeyHash(ctx context.Context, tx *sql.Tx) error {
+	// The plaintext keys are gone once hashed, so there is nothing to restore
+	return errors.New("bearer key hashing cannot be rolled back: plaintext keys are not recoverable")
+}
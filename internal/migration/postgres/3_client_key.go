@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientKey, downClientKey)
+}
+
+func upClientKey(ctx context.Context, tx *sql.Tx) error {
+	// Superseded keys are kept per version and stay valid until expires_at
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients ADD COLUMN IF NOT EXISTS key_version INTEGER DEFAULT 1 NOT NULL;
+		CREATE TABLE IF NOT EXISTS client_keys (
+			id SERIAL PRIMARY KEY,
+			client_id INTEGER NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+			version INTEGER NOT NULL,
+			key_prefix VARCHAR(16) NOT NULL,
+			bearer_key_hash VARCHAR(64) UNIQUE NOT NULL,
+			rotated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			UNIQUE (client_id, version)
+		);
+		CREATE INDEX IF NOT EXISTS client_keys_key_prefix_idx ON client_keys (key_prefix);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientKey(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS client_keys;
+		ALTER TABLE clients DROP COLUMN IF EXISTS key_version;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
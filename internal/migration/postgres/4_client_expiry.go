@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientExpiry, downClientExpiry)
+}
+
+func upClientExpiry(ctx context.Context, tx *sql.Tx) error {
+	// NULL means the key never expires, is not revoked or has not been used yet
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP,
+		ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP,
+		ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientExpiry(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		DROP COLUMN IF EXISTS expires_at,
+		DROP COLUMN IF EXISTS revoked_at,
+		DROP COLUMN IF EXISTS last_used_at;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
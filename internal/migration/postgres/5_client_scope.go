@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientScope, downClientScope)
+}
+
+func upClientScope(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS client_scopes (
+		client_id INTEGER NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+		scope VARCHAR(100) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		PRIMARY KEY (client_id, scope)
+	);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientScope(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS client_scopes;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
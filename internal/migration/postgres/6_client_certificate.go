@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientCertificate, downClientCertificate)
+}
+
+func upClientCertificate(ctx context.Context, tx *sql.Tx) error {
+	// Empty means the client cannot authenticate with a certificate
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		ADD COLUMN IF NOT EXISTS certificate_subject VARCHAR(512) NOT NULL DEFAULT '';`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_certificate_subject
+		ON clients (certificate_subject) WHERE certificate_subject <> '';`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientCertificate(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS idx_clients_certificate_subject;`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `ALTER TABLE clients DROP COLUMN IF EXISTS certificate_subject;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientSigningSecret, downClientSigningSecret)
+}
+
+func upClientSigningSecret(ctx context.Context, tx *sql.Tx) error {
+	// Sealed with SECRET_ENCRYPTION_KEY; empty means the client cannot sign requests
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		ADD COLUMN IF NOT EXISTS signing_secret TEXT NOT NULL DEFAULT '';`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientSigningSecret(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients DROP COLUMN IF EXISTS signing_secret;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientRateLimit, downClientRateLimit)
+}
+
+func upClientRateLimit(ctx context.Context, tx *sql.Tx) error {
+	// Requests per RATE_LIMIT_WINDOW; 0 falls back to RATE_LIMIT_DEFAULT
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients
+		ADD COLUMN IF NOT EXISTS rate_limit INTEGER NOT NULL DEFAULT 0;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientRateLimit(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE clients DROP COLUMN IF EXISTS rate_limit;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
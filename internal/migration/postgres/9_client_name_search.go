@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientNameSearch, downClientNameSearch)
+}
+
+func upClientNameSearch(ctx context.Context, tx *sql.Tx) error {
+	// Serves the case-insensitive name prefix search, lower(name) LIKE 'prefix%'
+	_, err := tx.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_clients_name_lower
+		ON clients (lower(name) varchar_pattern_ops);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientNameSearch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS idx_clients_name_lower;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
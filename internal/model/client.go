@@ -1,9 +1,11 @@
 package model
 
 import (
+	"sort"
+	"strings"
 	"time"
 
-	"go-template/utils"
+	"go-template/utils/apikey"
 )
 
 const (
@@ -12,31 +14,153 @@ const (
 )
 
 type Client struct {
-	ID int `json:"id" db:"id" gorm:"primaryKey"`
+	ID         int        `json:"id" db:"id" gorm:"primaryKey"`
+	KeyVersion int        `json:"key_version" db:"key_version" gorm:"default:1"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	// SigningSecret is returned once when issued; only the sealed form is stored
+	SigningSecret       string `json:"signing_secret,omitempty" db:"-" gorm:"-"`
+	SealedSigningSecret string `json:"-" db:"signing_secret" gorm:"column:signing_secret"`
 	ClientInput
 }
 
+// ClientInput carries the plaintext BearerKey only on its way in, and back out
+// once when the key was generated; only the prefix and hash are persisted.
+// The validate tags are enforced by the client domain for every entry point.
 type ClientInput struct {
-	Name      string    `json:"name" db:"name"`
-	BearerKey string    `json:"bearer_key,omitempty" db:"bearer_key" gorm:"unique"`
+	Name          string     `json:"name" db:"name" validate:"required,max=100"`
+	BearerKey     string     `json:"bearer_key,omitempty" db:"-" gorm:"-" validate:"omitempty,bearer_key"`
+	KeyPrefix     string     `json:"key_prefix" db:"key_prefix" gorm:"index"`
+	BearerKeyHash string     `json:"-" db:"bearer_key_hash" gorm:"unique"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	// CertificateSubject is the client certificate identity (URI, DNS or email SAN,
+	// or subject) accepted by the mtls auth driver
+	CertificateSubject string `json:"certificate_subject,omitempty" db:"certificate_subject" validate:"max=512"`
+	// RateLimit is requests per RATE_LIMIT_WINDOW; 0 uses RATE_LIMIT_DEFAULT
+	RateLimit int `json:"rate_limit,omitempty" db:"rate_limit" validate:"min=0"`
+	// Scopes replaces the stored scopes on upsert when set; they live in client_scopes
+	Scopes    []string  `json:"scopes,omitempty" db:"-" gorm:"-" validate:"dive,max=100"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type ClientFilter struct {
-	IDs        []int    `json:"ids"`
-	Names      []string `json:"names"`
-	BearerKeys []string `json:"bearer_keys"`
+	IDs         []int    `json:"ids" form:"ids" validate:"dive,gt=0"`
+	Names       []string `json:"names" form:"names" validate:"dive,max=100"`
+	KeyPrefixes []string `json:"key_prefixes" form:"key_prefixes" validate:"dive,max=16"`
+	// BearerKeys are never read from a query string, where they would end up in access logs
+	BearerKeys      []string `json:"bearer_keys" form:"-" validate:"dive,max=255"`
+	BearerKeyHashes []string `json:"-" form:"-"`
+	// CertificateSubjects matches clients by certificate identity
+	CertificateSubjects []string `json:"certificate_subjects" form:"certificate_subjects" validate:"dive,max=512"`
+	// Search matches names starting with it, ignoring case
+	Search string `json:"search" form:"search" validate:"max=100"`
+
+	// Limit, Sort and Cursor page through the matches of FindByFilter; a zero
+	// Limit on the database port returns every match
+	Limit int `json:"limit" form:"limit" validate:"min=0,max=500"`
+	// Sort is "id" (default) or "-id" for newest first
+	Sort string `json:"sort" form:"sort" validate:"omitempty,oneof=id -id"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `json:"cursor" form:"cursor" validate:"max=64"`
+	// AfterID is the decoded Cursor
+	AfterID int `json:"-" form:"-"`
+}
+
+const (
+	ClientSortID     = "id"
+	ClientSortIDDesc = "-id"
+)
+
+// ClientPatch changes only the fields it carries; the bearer key is changed by rotation
+type ClientPatch struct {
+	Name               *string    `json:"name"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	CertificateSubject *string    `json:"certificate_subject"`
+	RateLimit          *int       `json:"rate_limit"`
+	// Scopes replaces the stored scopes when set; an empty list removes them all
+	Scopes []string `json:"scopes"`
 }
 
 func ClientPrepare(v *ClientInput) {
 	v.CreatedAt = time.Now()
 	v.UpdatedAt = time.Now()
 	if v.BearerKey == "" {
-		v.BearerKey = utils.GenerateSecureToken(25)
+		v.BearerKey = apikey.Generate()
+	}
+	v.KeyPrefix = apikey.Prefix(v.BearerKey)
+	v.BearerKeyHash = apikey.Hash(v.BearerKey)
+	if v.Scopes != nil {
+		v.Scopes = normalizeScopes(v.Scopes)
+	}
+}
+
+// normalizeScopes trims, de-duplicates and sorts scopes, dropping empty ones
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		normalized = append(normalized, scope)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ClientPatchApply copies the fields carried by p onto v
+func ClientPatchApply(v *ClientInput, p ClientPatch) {
+	if p.Name != nil {
+		v.Name = *p.Name
+	}
+	if p.ExpiresAt != nil {
+		v.ExpiresAt = p.ExpiresAt
+	}
+	if p.CertificateSubject != nil {
+		v.CertificateSubject = *p.CertificateSubject
+	}
+	if p.RateLimit != nil {
+		v.RateLimit = *p.RateLimit
+	}
+	if p.Scopes != nil {
+		v.Scopes = normalizeScopes(p.Scopes)
+	}
+}
+
+// ClientFilterPrepare hashes the plaintext bearer keys so they can be matched at rest
+func ClientFilterPrepare(v *ClientFilter) {
+	for _, bearerKey := range v.BearerKeys {
+		v.BearerKeyHashes = append(v.BearerKeyHashes, apikey.Hash(bearerKey))
+	}
+	v.BearerKeys = nil
+}
+
+// ClientFilterUnpaged drops the paging fields, so a filter that deletes or
+// revokes every match also finds every match whose cached key must be purged
+func ClientFilterUnpaged(v *ClientFilter) {
+	v.Limit = 0
+	v.Sort = ""
+	v.Cursor = ""
+	v.AfterID = 0
+}
+
+// IsActive reports whether the client key is neither revoked nor expired at the given time
+func (c Client) IsActive(at time.Time) bool {
+	if c.RevokedAt != nil {
+		return false
 	}
+	return c.ExpiresAt == nil || at.Before(*c.ExpiresAt)
 }
 
+// IsEmpty reports whether the filter matches every client; paging fields do not narrow it
 func (c ClientFilter) IsEmpty() bool {
-	return len(c.IDs) == 0 && len(c.Names) == 0 && len(c.BearerKeys) == 0
+	return len(c.IDs) == 0 && len(c.Names) == 0 && len(c.KeyPrefixes) == 0 &&
+		len(c.BearerKeys) == 0 && len(c.BearerKeyHashes) == 0 && len(c.CertificateSubjects) == 0 &&
+		c.Search == ""
 }
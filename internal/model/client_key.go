@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ClientKey is a superseded bearer key that stays valid until ExpiresAt
+type ClientKey struct {
+	ID            int       `json:"id" db:"id" gorm:"primaryKey"`
+	ClientID      int       `json:"client_id" db:"client_id" gorm:"index"`
+	Version       int       `json:"version" db:"version"`
+	KeyPrefix     string    `json:"key_prefix" db:"key_prefix" gorm:"index"`
+	BearerKeyHash string    `json:"-" db:"bearer_key_hash" gorm:"unique"`
+	RotatedAt     time.Time `json:"rotated_at" db:"rotated_at"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+}
+
+type ClientKeyFilter struct {
+	ClientIDs   []int
+	KeyPrefixes []string
+	// ActiveAt keeps only keys whose grace period has not ended by then
+	ActiveAt time.Time
+}
+
+type ClientRotateInput struct {
+	ID int `json:"id"`
+	// GracePeriod keeps the previous key valid, e.g. "1h"; empty uses BEARER_KEY_GRACE_PERIOD
+	GracePeriod string `json:"grace_period"`
+}
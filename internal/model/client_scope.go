@@ -0,0 +1,10 @@
+package model
+
+type ClientScope struct {
+	ClientID int    `json:"client_id" db:"client_id" gorm:"primaryKey"`
+	Scope    string `json:"scope" db:"scope" gorm:"primaryKey"`
+}
+
+type ClientScopeFilter struct {
+	ClientIDs []int
+}
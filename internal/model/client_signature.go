@@ -0,0 +1,16 @@
+package model
+
+type ClientSigningSecretInput struct {
+	ID int `json:"id"`
+}
+
+// SignedRequest carries what the HMAC auth driver needs to verify a request
+type SignedRequest struct {
+	ClientID   string
+	Method     string
+	RequestURI string
+	Timestamp  string
+	Nonce      string
+	Signature  string
+	Body       []byte
+}
@@ -0,0 +1,15 @@
+package model
+
+// Credentials is what a caller presented, gathered by an inbound adapter from
+// its transport for the auth domain to resolve into a Principal
+type Credentials struct {
+	// IP keys lockouts of callers that keep failing
+	IP          string
+	BearerToken string
+	// CertificateIdentities come from the client certificate verified during
+	// the TLS handshake, nil when the caller presented none
+	CertificateIdentities []string
+	// Signed is nil unless the transport gathered a signed request, which
+	// only HTTP can carry
+	Signed *SignedRequest
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+type Health struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func (h Health) IsUp() bool {
+	return h.Status == HealthStatusUp
+}
@@ -0,0 +1,9 @@
+package model
+
+// InternalAudit is the audit record written for every request to an internal route
+type InternalAudit struct {
+	Credential string `json:"credential"`
+	Method     string `json:"method"`
+	Route      string `json:"route"`
+	Status     int    `json:"status"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const (
+	// LockoutScopeClient counts failed client bearer keys
+	LockoutScopeClient = "client"
+	// LockoutScopeInternal counts failed INTERNAL_KEY attempts
+	LockoutScopeInternal = "internal"
+)
+
+// Lockout is the audit record written when a caller is locked out
+type Lockout struct {
+	Scope    string        `json:"scope"`
+	Subject  string        `json:"subject"`
+	Failures int64         `json:"failures"`
+	Strikes  int64         `json:"strikes"`
+	Duration time.Duration `json:"duration"`
+	Until    time.Time     `json:"until"`
+}
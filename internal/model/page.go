@@ -0,0 +1,33 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// Page tells where a listing stopped, next to the results of the page
+type Page struct {
+	// NextCursor fetches the following page when sent back as cursor; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Total counts every match of the filter across all pages
+	Total int64 `json:"total"`
+}
+
+// EncodeCursor returns the opaque cursor of the page that follows id
+func EncodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+// DecodeCursor returns the id a cursor made by EncodeCursor follows
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(string(raw))
+	if err != nil || id <= 0 {
+		return 0, errors.New("malformed cursor")
+	}
+	return id, nil
+}
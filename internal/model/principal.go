@@ -0,0 +1,62 @@
+package model
+
+import (
+	"context"
+
+	"go-template/utils/activity"
+)
+
+const (
+	PrincipalSourceClient = "client"
+	PrincipalSourceJWT    = "jwt"
+	// PrincipalSourceIntrospection marks opaque tokens resolved by an RFC 7662 endpoint
+	PrincipalSourceIntrospection = "introspection"
+	// PrincipalSourceCertificate marks clients identified by a verified TLS client certificate
+	PrincipalSourceCertificate = "certificate"
+	// PrincipalSourceSignature marks clients that signed the request with their HMAC secret
+	PrincipalSourceSignature = "signature"
+	// PrincipalSourceInternal marks operators and services holding a named internal key
+	PrincipalSourceInternal = "internal"
+)
+
+// Principal is the authenticated caller, whichever auth driver identified it
+type Principal struct {
+	Subject string   `json:"subject"`
+	Source  string   `json:"source"`
+	Scopes  []string `json:"scopes"`
+	// ClientID and Name are set for bearer key, certificate and signature clients.
+	// Internal key callers carry their credential name
+	ClientID int    `json:"client_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	// RateLimit overrides the default rate limit when positive
+	RateLimit int `json:"rate_limit,omitempty"`
+	// Issuer and Claims are set for JWT and introspected token callers
+	Issuer string                 `json:"issuer,omitempty"`
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+// HasScopes reports whether the principal was granted every one of the scopes
+func (p Principal) HasScopes(scopes ...string) bool {
+	granted := make(map[string]struct{}, len(p.Scopes))
+	for _, scope := range p.Scopes {
+		granted[scope] = struct{}{}
+	}
+	for _, scope := range scopes {
+		if _, ok := granted[scope]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContextWithPrincipal records the caller in the activity context, so logs carry its client_id
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	ctx = activity.WithClientID(ctx, principal.Subject)
+	return activity.WithPrincipal(ctx, principal)
+}
+
+// PrincipalFromContext returns the caller set by ContextWithPrincipal
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := activity.GetPrincipal(ctx).(Principal)
+	return principal, ok
+}
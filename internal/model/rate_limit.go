@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// RateLimitResult describes one request against the caller's rate limit.
+// A zero Limit means the caller is not limited
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
@@ -1,7 +1,14 @@
 package model
 
+import "go-template/utils/apperror"
+
 type Response struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
-	Data    any    `json:"data,omitempty"`
+	Code    string `json:"code,omitempty"`
+	// Details lists each invalid field of a validation error
+	Details []apperror.FieldError `json:"details,omitempty"`
+	Data    any                   `json:"data,omitempty"`
+	// Page is set on listings
+	Page *Page `json:"page,omitempty"`
 }
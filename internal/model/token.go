@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// TokenIntrospection is the cached outcome of introspecting an opaque access token
+type TokenIntrospection struct {
+	Active    bool      `json:"active"`
+	Principal Principal `json:"principal"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -1,20 +1,42 @@
 package inbound_port
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	clientv1 "go-template/proto/client/v1"
+)
 
 type ClientHttpPort interface {
 	Upsert(c *gin.Context)
 	Find(c *gin.Context)
 	Delete(c *gin.Context)
+	Rotate(c *gin.Context)
+	Revoke(c *gin.Context)
+	SigningSecret(c *gin.Context)
+	// Resource routes of /internal/v1/clients
+	List(c *gin.Context)
+	Get(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	Patch(c *gin.Context)
+	Remove(c *gin.Context)
+}
+
+type ClientGrpcPort interface {
+	clientv1.ClientServiceServer
 }
 
 type ClientMessagePort interface {
-	Upsert(a any) bool
+	Upsert(ctx context.Context, a any) bool
 }
 
 type ClientCommandPort interface {
 	PublishUpsert(name string)
 	StartUpsert(name string)
+	RotateKey(id int, gracePeriod string)
+	IssueSigningSecret(id int)
 }
 
 type ClientWorkflowPort interface {
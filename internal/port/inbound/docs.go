@@ -0,0 +1,8 @@
+package inbound_port
+
+import "github.com/gin-gonic/gin"
+
+type DocsHttpPort interface {
+	OpenAPI(c *gin.Context)
+	SwaggerUI(c *gin.Context)
+}
@@ -0,0 +1,15 @@
+package inbound_port
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type HealthHttpPort interface {
+	Liveness(c *gin.Context)
+	Readiness(c *gin.Context)
+}
+
+type HealthGrpcPort interface {
+	grpc_health_v1.HealthServer
+}
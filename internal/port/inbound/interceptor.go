@@ -0,0 +1,11 @@
+package inbound_port
+
+import "google.golang.org/grpc"
+
+type InterceptorGrpcPort interface {
+	InternalAuth() grpc.UnaryServerInterceptor
+	ClientAuth() grpc.UnaryServerInterceptor
+	RateLimit() grpc.UnaryServerInterceptor
+	ErrorHandler() grpc.UnaryServerInterceptor
+	Tracing() grpc.UnaryServerInterceptor
+}
@@ -6,4 +6,10 @@ import "github.com/gin-gonic/gin"
 type MiddlewareHttpPort interface {
 	InternalAuth() gin.HandlerFunc
 	ClientAuth() gin.HandlerFunc
+	SignatureAuth() gin.HandlerFunc
+	RequireScopes(scopes ...string) gin.HandlerFunc
+	RateLimit() gin.HandlerFunc
+	ErrorHandler() gin.HandlerFunc
+	Metrics() gin.HandlerFunc
+	Tracing() gin.HandlerFunc
 }
@@ -1,7 +1,15 @@
 package inbound_port
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+
+	pingv1 "go-template/proto/ping/v1"
+)
 
 type PingHttpPort interface {
 	GetResource(c *gin.Context)
 }
+
+type PingGrpcPort interface {
+	pingv1.PingServiceServer
+}
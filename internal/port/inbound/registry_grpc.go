@@ -0,0 +1,8 @@
+package inbound_port
+
+type GrpcPort interface {
+	Interceptor() InterceptorGrpcPort
+	Health() HealthGrpcPort
+	Ping() PingGrpcPort
+	Client() ClientGrpcPort
+}
@@ -3,5 +3,7 @@ package inbound_port
 type HttpPort interface {
 	Middleware() MiddlewareHttpPort
 	Ping() PingHttpPort
+	Health() HealthHttpPort
 	Client() ClientHttpPort
+	Docs() DocsHttpPort
 }
@@ -1,24 +1,53 @@
 package outbound_port
 
-import "go-template/internal/model"
+import (
+	"context"
+	"time"
+
+	"go-template/internal/model"
+)
 
 //go:generate mockgen -source=client.go -destination=./../../../tests/mocks/port/mock_client.go
 type ClientDatabasePort interface {
-	Upsert(datas []model.ClientInput) error
-	FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error)
-	DeleteByFilter(filter model.ClientFilter) error
-	IsExists(bearerKey string) (bool, error)
+	Upsert(ctx context.Context, datas []model.ClientInput) error
+	// Insert creates a client, failing with a conflict instead of updating an existing one
+	Insert(ctx context.Context, data model.ClientInput) error
+	// FindByFilter returns the matches ordered by filter.Sort, one page of them when filter.Limit is set
+	FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error)
+	// CountByFilter counts every match of filter, ignoring its paging fields
+	CountByFilter(ctx context.Context, filter model.ClientFilter) (int64, error)
+	DeleteByFilter(ctx context.Context, filter model.ClientFilter) error
+	// Update replaces the editable fields of a client, found by ID
+	Update(ctx context.Context, data model.Client) error
+	UpdateKey(ctx context.Context, data model.Client) error
+	UpdateSigningSecret(ctx context.Context, data model.Client) error
+	RevokeByFilter(ctx context.Context, filter model.ClientFilter, at time.Time) error
+	UpdateLastUsed(ctx context.Context, usage map[int]time.Time) error
+}
+
+type ClientKeyDatabasePort interface {
+	Insert(ctx context.Context, data model.ClientKey) error
+	FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error)
+}
+
+type ClientScopeDatabasePort interface {
+	FindByFilter(ctx context.Context, filter model.ClientScopeFilter) ([]model.ClientScope, error)
+	Replace(ctx context.Context, clientID int, scopes []string) error
 }
 
 type ClientMessagePort interface {
-	PublishUpsert(datas []model.ClientInput) error
+	PublishUpsert(ctx context.Context, datas []model.ClientInput) error
 }
 
 type ClientCachePort interface {
-	Set(data model.Client) error
-	Get(bearerKey string) (model.Client, error)
+	Set(ctx context.Context, data model.Client) error
+	Get(ctx context.Context, bearerKeyHash string) (model.Client, error)
+	// SetUnknown caches that no client owns bearerKeyHash, so repeated guesses skip the database
+	SetUnknown(ctx context.Context, bearerKeyHash string, ttl time.Duration) error
+	Delete(ctx context.Context, bearerKeyHash string) error
+	DeleteMany(ctx context.Context, bearerKeyHashes []string) error
 }
 
 type ClientWorkflowPort interface {
-	StartUpsert(data model.ClientInput) error
+	StartUpsert(ctx context.Context, data model.ClientInput) error
 }
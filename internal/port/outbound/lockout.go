@@ -0,0 +1,20 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=lockout.go -destination=./../../../tests/mocks/port/mock_lockout.go
+type LockoutCachePort interface {
+	// AddFailure counts a failed attempt for key, forgetting them window after the first
+	AddFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	// AddStrike counts a lockout for key, forgetting them ttl after the last one
+	AddStrike(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Lock locks key out for ttl and clears its failures
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockedFor reports how long key stays locked out, zero when it is not
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// ResetFailures forgets the failed attempts of key, but not its strikes
+	ResetFailures(ctx context.Context, key string) error
+}
@@ -0,0 +1,12 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=nonce.go -destination=./../../../tests/mocks/port/mock_nonce.go
+type NonceCachePort interface {
+	// Claim records the nonce for ttl and reports false when it was already seen
+	Claim(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
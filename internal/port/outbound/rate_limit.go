@@ -0,0 +1,14 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+
+	"go-template/internal/model"
+)
+
+//go:generate mockgen -source=rate_limit.go -destination=./../../../tests/mocks/port/mock_rate_limit.go
+type RateLimitCachePort interface {
+	// Allow counts the request against key when it fits under limit per window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (model.RateLimitResult, error)
+}
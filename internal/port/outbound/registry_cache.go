@@ -1,6 +1,13 @@
 package outbound_port
 
+import "context"
+
 //go:generate mockgen -source=registry_cache.go -destination=./../../../tests/mocks/port/mock_registry_cache.go
 type CachePort interface {
 	Client() ClientCachePort
+	Token() TokenCachePort
+	Nonce() NonceCachePort
+	RateLimit() RateLimitCachePort
+	Lockout() LockoutCachePort
+	Ping(ctx context.Context) error
 }
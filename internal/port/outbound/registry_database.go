@@ -1,13 +1,20 @@
 package outbound_port
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 //go:generate mockgen -source=registry_database.go -destination=./../../../tests/mocks/port/mock_registry_database.go
 type InTransaction func(repoRegistry DatabasePort) (interface{}, error)
 
 type DatabasePort interface {
 	Client() ClientDatabasePort
-	DoInTransaction(txFunc InTransaction) (out interface{}, err error)
+	ClientKey() ClientKeyDatabasePort
+	ClientScope() ClientScopeDatabasePort
+	DoInTransaction(ctx context.Context, txFunc InTransaction) (out interface{}, err error)
+	Ping(ctx context.Context) error
 }
 
 // DatabaseExecutor is now GORM's *gorm.DB
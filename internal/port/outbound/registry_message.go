@@ -1,6 +1,9 @@
 package outbound_port
 
+import "context"
+
 //go:generate mockgen -source=registry_message.go -destination=./../../../tests/mocks/port/mock_registry_message.go
 type MessagePort interface {
 	Client() ClientMessagePort
+	Ping(ctx context.Context) error
 }
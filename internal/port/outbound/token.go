@@ -0,0 +1,14 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+
+	"go-template/internal/model"
+)
+
+//go:generate mockgen -source=token.go -destination=./../../../tests/mocks/port/mock_token.go
+type TokenCachePort interface {
+	Set(ctx context.Context, tokenHash string, data model.TokenIntrospection, ttl time.Duration) error
+	Get(ctx context.Context, tokenHash string) (model.TokenIntrospection, error)
+}
@@ -16,7 +16,7 @@ func (c *ClientTestData) ValidClientInput() model.ClientInput {
 	now := time.Now()
 	return model.ClientInput{
 		Name:      "Test Client",
-		BearerKey: "test-bearer-key-" + now.Format("20060102150405"),
+		BearerKey: "test-bearer-key-" + now.Format("20060102150405.000000"),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -60,7 +60,7 @@ func (c *ClientTestData) MultipleClientInputs(count int) []model.ClientInput {
 	for i := 0; i < count; i++ {
 		inputs[i] = model.ClientInput{
 			Name:      "Client " + string(rune('A'+i)),
-			BearerKey: "key-" + string(rune('a'+i)) + "-" + now.Format("150405"),
+			BearerKey: "multiple-client-key-" + string(rune('a'+i)) + "-" + now.Format("20060102150405"),
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
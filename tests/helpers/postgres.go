@@ -24,7 +24,8 @@ func SetupPostgresContainer(ctx context.Context) (*PostgresContainer, error) {
 	// 1. Check if external DB DSN is provided
 	if dsn := os.Getenv("TEST_DB_DSN"); dsn != "" {
 		db, err := gorm.Open(postgresDriver.Open(dsn), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Silent),
+			Logger:         logger.Default.LogMode(logger.Silent),
+			TranslateError: true,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to external database: %w", err)
@@ -57,7 +58,8 @@ func SetupPostgresContainer(ctx context.Context) (*PostgresContainer, error) {
 	}
 
 	db, err := gorm.Open(postgresDriver.Open(connStr), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
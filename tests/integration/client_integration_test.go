@@ -15,6 +15,7 @@ import (
 	postgres_outbound_adapter "go-template/internal/adapter/outbound/postgres"
 	"go-template/internal/model"
 	"go-template/tests/helpers"
+	"go-template/utils/apikey"
 )
 
 func TestClientIntegration(t *testing.T) {
@@ -48,62 +49,60 @@ func TestClientIntegration(t *testing.T) {
 			input := model.ClientInput{
 				Name:      "Integration Test Client",
 				BearerKey: bearerKey,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
 			}
+			model.ClientPrepare(&input)
+			hashFilter := model.ClientFilter{BearerKeyHashes: []string{input.BearerKeyHash}}
 
 			Convey("Upsert creates a new client", func() {
-				err := adapter.Upsert([]model.ClientInput{input})
+				err := adapter.Upsert(ctx, []model.ClientInput{input})
 				So(err, ShouldBeNil)
 
 				Convey("FindByFilter retrieves the client", func() {
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					results, err := adapter.FindByFilter(filter, false)
+					results, err := adapter.FindByFilter(ctx, hashFilter, false)
 					So(err, ShouldBeNil)
 					So(len(results), ShouldEqual, 1)
 					So(results[0].Name, ShouldEqual, "Integration Test Client")
-					So(results[0].BearerKey, ShouldEqual, bearerKey)
+					So(results[0].BearerKey, ShouldBeEmpty)
+					So(apikey.Verify(bearerKey, results[0].BearerKeyHash), ShouldBeTrue)
 				})
 
-				Convey("IsExists returns true for existing client", func() {
-					exists, err := adapter.IsExists(bearerKey)
+				Convey("FindByFilter finds the client by key prefix", func() {
+					results, err := adapter.FindByFilter(ctx, model.ClientFilter{KeyPrefixes: []string{input.KeyPrefix}}, false)
 					So(err, ShouldBeNil)
-					So(exists, ShouldBeTrue)
+					So(len(results), ShouldEqual, 1)
 				})
 
 				Convey("Upsert updates existing client", func() {
 					updatedInput := model.ClientInput{
 						Name:      "Updated Client Name",
 						BearerKey: bearerKey,
-						CreatedAt: input.CreatedAt,
-						UpdatedAt: time.Now(),
 					}
-					err := adapter.Upsert([]model.ClientInput{updatedInput})
+					model.ClientPrepare(&updatedInput)
+					err := adapter.Upsert(ctx, []model.ClientInput{updatedInput})
 					So(err, ShouldBeNil)
 
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					results, err := adapter.FindByFilter(filter, false)
+					results, err := adapter.FindByFilter(ctx, hashFilter, false)
 					So(err, ShouldBeNil)
 					So(len(results), ShouldEqual, 1)
 					So(results[0].Name, ShouldEqual, "Updated Client Name")
 				})
 
 				Convey("DeleteByFilter removes the client", func() {
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					err := adapter.DeleteByFilter(filter)
+					err := adapter.DeleteByFilter(ctx, hashFilter)
 					So(err, ShouldBeNil)
 
-					exists, err := adapter.IsExists(bearerKey)
+					results, err := adapter.FindByFilter(ctx, hashFilter, false)
 					So(err, ShouldBeNil)
-					So(exists, ShouldBeFalse)
+					So(len(results), ShouldEqual, 0)
 				})
 			})
 		})
 
-		Convey("IsExists returns false for non-existent client", func() {
-			exists, err := adapter.IsExists("nonexistent-key-" + time.Now().Format("20060102150405"))
+		Convey("FindByFilter returns nothing for non-existent key hash", func() {
+			filter := model.ClientFilter{BearerKeyHashes: []string{apikey.Hash("nonexistent-key-" + time.Now().Format("20060102150405"))}}
+			results, err := adapter.FindByFilter(ctx, filter, false)
 			So(err, ShouldBeNil)
-			So(exists, ShouldBeFalse)
+			So(len(results), ShouldEqual, 0)
 		})
 
 		Convey("FindByFilter with multiple filters", func() {
@@ -112,15 +111,18 @@ func TestClientIntegration(t *testing.T) {
 
 			now := time.Now()
 			clients := []model.ClientInput{
-				{Name: "Client A", BearerKey: "key-a-" + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
-				{Name: "Client B", BearerKey: "key-b-" + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
+				{Name: "Client A", BearerKey: "key-a-" + now.Format("150405.000")},
+				{Name: "Client B", BearerKey: "key-b-" + now.Format("150405.000")},
+			}
+			for i := range clients {
+				model.ClientPrepare(&clients[i])
 			}
 
-			err := adapter.Upsert(clients)
+			err := adapter.Upsert(ctx, clients)
 			So(err, ShouldBeNil)
 
 			filter := model.ClientFilter{Names: []string{"Client A", "Client B"}}
-			results, err := adapter.FindByFilter(filter, false)
+			results, err := adapter.FindByFilter(ctx, filter, false)
 			So(err, ShouldBeNil)
 			So(len(results), ShouldBeGreaterThanOrEqualTo, 2)
 		})
@@ -5,8 +5,10 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	model "go-template/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,62 +36,250 @@ func (m *MockClientDatabasePort) EXPECT() *MockClientDatabasePortMockRecorder {
 	return m.recorder
 }
 
+// CountByFilter mocks base method.
+func (m *MockClientDatabasePort) CountByFilter(ctx context.Context, filter model.ClientFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByFilter", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByFilter indicates an expected call of CountByFilter.
+func (mr *MockClientDatabasePortMockRecorder) CountByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).CountByFilter), ctx, filter)
+}
+
 // DeleteByFilter mocks base method.
-func (m *MockClientDatabasePort) DeleteByFilter(filter model.ClientFilter) error {
+func (m *MockClientDatabasePort) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByFilter", filter)
+	ret := m.ctrl.Call(m, "DeleteByFilter", ctx, filter)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByFilter indicates an expected call of DeleteByFilter.
-func (mr *MockClientDatabasePortMockRecorder) DeleteByFilter(filter interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) DeleteByFilter(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).DeleteByFilter), filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).DeleteByFilter), ctx, filter)
 }
 
 // FindByFilter mocks base method.
-func (m *MockClientDatabasePort) FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error) {
+func (m *MockClientDatabasePort) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByFilter", filter, lock)
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter, lock)
 	ret0, _ := ret[0].([]model.Client)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByFilter indicates an expected call of FindByFilter.
-func (mr *MockClientDatabasePortMockRecorder) FindByFilter(filter, lock interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) FindByFilter(ctx, filter, lock interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).FindByFilter), filter, lock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).FindByFilter), ctx, filter, lock)
 }
 
-// IsExists mocks base method.
-func (m *MockClientDatabasePort) IsExists(bearerKey string) (bool, error) {
+// Insert mocks base method.
+func (m *MockClientDatabasePort) Insert(ctx context.Context, data model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsExists", bearerKey)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Insert", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockClientDatabasePortMockRecorder) Insert(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockClientDatabasePort)(nil).Insert), ctx, data)
+}
+
+// RevokeByFilter mocks base method.
+func (m *MockClientDatabasePort) RevokeByFilter(ctx context.Context, filter model.ClientFilter, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeByFilter", ctx, filter, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeByFilter indicates an expected call of RevokeByFilter.
+func (mr *MockClientDatabasePortMockRecorder) RevokeByFilter(ctx, filter, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).RevokeByFilter), ctx, filter, at)
+}
+
+// Update mocks base method.
+func (m *MockClientDatabasePort) Update(ctx context.Context, data model.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockClientDatabasePortMockRecorder) Update(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockClientDatabasePort)(nil).Update), ctx, data)
+}
+
+// UpdateKey mocks base method.
+func (m *MockClientDatabasePort) UpdateKey(ctx context.Context, data model.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKey", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKey indicates an expected call of UpdateKey.
+func (mr *MockClientDatabasePortMockRecorder) UpdateKey(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKey", reflect.TypeOf((*MockClientDatabasePort)(nil).UpdateKey), ctx, data)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *MockClientDatabasePort) UpdateLastUsed(ctx context.Context, usage map[int]time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", ctx, usage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockClientDatabasePortMockRecorder) UpdateLastUsed(ctx, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockClientDatabasePort)(nil).UpdateLastUsed), ctx, usage)
+}
+
+// UpdateSigningSecret mocks base method.
+func (m *MockClientDatabasePort) UpdateSigningSecret(ctx context.Context, data model.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSigningSecret", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// IsExists indicates an expected call of IsExists.
-func (mr *MockClientDatabasePortMockRecorder) IsExists(bearerKey interface{}) *gomock.Call {
+// UpdateSigningSecret indicates an expected call of UpdateSigningSecret.
+func (mr *MockClientDatabasePortMockRecorder) UpdateSigningSecret(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExists", reflect.TypeOf((*MockClientDatabasePort)(nil).IsExists), bearerKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSigningSecret", reflect.TypeOf((*MockClientDatabasePort)(nil).UpdateSigningSecret), ctx, data)
 }
 
 // Upsert mocks base method.
-func (m *MockClientDatabasePort) Upsert(datas []model.ClientInput) error {
+func (m *MockClientDatabasePort) Upsert(ctx context.Context, datas []model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upsert", datas)
+	ret := m.ctrl.Call(m, "Upsert", ctx, datas)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Upsert indicates an expected call of Upsert.
-func (mr *MockClientDatabasePortMockRecorder) Upsert(datas interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) Upsert(ctx, datas interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientDatabasePort)(nil).Upsert), ctx, datas)
+}
+
+// MockClientKeyDatabasePort is a mock of ClientKeyDatabasePort interface.
+type MockClientKeyDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientKeyDatabasePortMockRecorder
+}
+
+// MockClientKeyDatabasePortMockRecorder is the mock recorder for MockClientKeyDatabasePort.
+type MockClientKeyDatabasePortMockRecorder struct {
+	mock *MockClientKeyDatabasePort
+}
+
+// NewMockClientKeyDatabasePort creates a new mock instance.
+func NewMockClientKeyDatabasePort(ctrl *gomock.Controller) *MockClientKeyDatabasePort {
+	mock := &MockClientKeyDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockClientKeyDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientKeyDatabasePort) EXPECT() *MockClientKeyDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// FindByFilter mocks base method.
+func (m *MockClientKeyDatabasePort) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.ClientKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFilter indicates an expected call of FindByFilter.
+func (mr *MockClientKeyDatabasePortMockRecorder) FindByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientKeyDatabasePort)(nil).FindByFilter), ctx, filter)
+}
+
+// Insert mocks base method.
+func (m *MockClientKeyDatabasePort) Insert(ctx context.Context, data model.ClientKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockClientKeyDatabasePortMockRecorder) Insert(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockClientKeyDatabasePort)(nil).Insert), ctx, data)
+}
+
+// MockClientScopeDatabasePort is a mock of ClientScopeDatabasePort interface.
+type MockClientScopeDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientScopeDatabasePortMockRecorder
+}
+
+// MockClientScopeDatabasePortMockRecorder is the mock recorder for MockClientScopeDatabasePort.
+type MockClientScopeDatabasePortMockRecorder struct {
+	mock *MockClientScopeDatabasePort
+}
+
+// NewMockClientScopeDatabasePort creates a new mock instance.
+func NewMockClientScopeDatabasePort(ctrl *gomock.Controller) *MockClientScopeDatabasePort {
+	mock := &MockClientScopeDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockClientScopeDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientScopeDatabasePort) EXPECT() *MockClientScopeDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// FindByFilter mocks base method.
+func (m *MockClientScopeDatabasePort) FindByFilter(ctx context.Context, filter model.ClientScopeFilter) ([]model.ClientScope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.ClientScope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFilter indicates an expected call of FindByFilter.
+func (mr *MockClientScopeDatabasePortMockRecorder) FindByFilter(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientDatabasePort)(nil).Upsert), datas)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientScopeDatabasePort)(nil).FindByFilter), ctx, filter)
+}
+
+// Replace mocks base method.
+func (m *MockClientScopeDatabasePort) Replace(ctx context.Context, clientID int, scopes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, clientID, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockClientScopeDatabasePortMockRecorder) Replace(ctx, clientID, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockClientScopeDatabasePort)(nil).Replace), ctx, clientID, scopes)
 }
 
 // MockClientMessagePort is a mock of ClientMessagePort interface.
@@ -116,17 +306,17 @@ func (m *MockClientMessagePort) EXPECT() *MockClientMessagePortMockRecorder {
 }
 
 // PublishUpsert mocks base method.
-func (m *MockClientMessagePort) PublishUpsert(datas []model.ClientInput) error {
+func (m *MockClientMessagePort) PublishUpsert(ctx context.Context, datas []model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PublishUpsert", datas)
+	ret := m.ctrl.Call(m, "PublishUpsert", ctx, datas)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PublishUpsert indicates an expected call of PublishUpsert.
-func (mr *MockClientMessagePortMockRecorder) PublishUpsert(datas interface{}) *gomock.Call {
+func (mr *MockClientMessagePortMockRecorder) PublishUpsert(ctx, datas interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishUpsert", reflect.TypeOf((*MockClientMessagePort)(nil).PublishUpsert), datas)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishUpsert", reflect.TypeOf((*MockClientMessagePort)(nil).PublishUpsert), ctx, datas)
 }
 
 // MockClientCachePort is a mock of ClientCachePort interface.
@@ -152,33 +342,75 @@ func (m *MockClientCachePort) EXPECT() *MockClientCachePortMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockClientCachePort) Delete(ctx context.Context, bearerKeyHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, bearerKeyHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockClientCachePortMockRecorder) Delete(ctx, bearerKeyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientCachePort)(nil).Delete), ctx, bearerKeyHash)
+}
+
+// DeleteMany mocks base method.
+func (m *MockClientCachePort) DeleteMany(ctx context.Context, bearerKeyHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMany", ctx, bearerKeyHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMany indicates an expected call of DeleteMany.
+func (mr *MockClientCachePortMockRecorder) DeleteMany(ctx, bearerKeyHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMany", reflect.TypeOf((*MockClientCachePort)(nil).DeleteMany), ctx, bearerKeyHashes)
+}
+
 // Get mocks base method.
-func (m *MockClientCachePort) Get(bearerKey string) (model.Client, error) {
+func (m *MockClientCachePort) Get(ctx context.Context, bearerKeyHash string) (model.Client, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", bearerKey)
+	ret := m.ctrl.Call(m, "Get", ctx, bearerKeyHash)
 	ret0, _ := ret[0].(model.Client)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockClientCachePortMockRecorder) Get(bearerKey interface{}) *gomock.Call {
+func (mr *MockClientCachePortMockRecorder) Get(ctx, bearerKeyHash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClientCachePort)(nil).Get), bearerKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClientCachePort)(nil).Get), ctx, bearerKeyHash)
 }
 
 // Set mocks base method.
-func (m *MockClientCachePort) Set(data model.Client) error {
+func (m *MockClientCachePort) Set(ctx context.Context, data model.Client) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", data)
+	ret := m.ctrl.Call(m, "Set", ctx, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockClientCachePortMockRecorder) Set(data interface{}) *gomock.Call {
+func (mr *MockClientCachePortMockRecorder) Set(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockClientCachePort)(nil).Set), ctx, data)
+}
+
+// SetUnknown mocks base method.
+func (m *MockClientCachePort) SetUnknown(ctx context.Context, bearerKeyHash string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUnknown", ctx, bearerKeyHash, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUnknown indicates an expected call of SetUnknown.
+func (mr *MockClientCachePortMockRecorder) SetUnknown(ctx, bearerKeyHash, ttl interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockClientCachePort)(nil).Set), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUnknown", reflect.TypeOf((*MockClientCachePort)(nil).SetUnknown), ctx, bearerKeyHash, ttl)
 }
 
 // MockClientWorkflowPort is a mock of ClientWorkflowPort interface.
@@ -205,15 +437,15 @@ func (m *MockClientWorkflowPort) EXPECT() *MockClientWorkflowPortMockRecorder {
 }
 
 // StartUpsert mocks base method.
-func (m *MockClientWorkflowPort) StartUpsert(data model.ClientInput) error {
+func (m *MockClientWorkflowPort) StartUpsert(ctx context.Context, data model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StartUpsert", data)
+	ret := m.ctrl.Call(m, "StartUpsert", ctx, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StartUpsert indicates an expected call of StartUpsert.
-func (mr *MockClientWorkflowPortMockRecorder) StartUpsert(data interface{}) *gomock.Call {
+func (mr *MockClientWorkflowPortMockRecorder) StartUpsert(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUpsert", reflect.TypeOf((*MockClientWorkflowPort)(nil).StartUpsert), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUpsert", reflect.TypeOf((*MockClientWorkflowPort)(nil).StartUpsert), ctx, data)
 }
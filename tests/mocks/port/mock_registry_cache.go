@@ -5,6 +5,7 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	outbound_port "go-template/internal/port/outbound"
 	reflect "reflect"
 
@@ -47,3 +48,73 @@ func (mr *MockCachePortMockRecorder) Client() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockCachePort)(nil).Client))
 }
+
+// Lockout mocks base method.
+func (m *MockCachePort) Lockout() outbound_port.LockoutCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lockout")
+	ret0, _ := ret[0].(outbound_port.LockoutCachePort)
+	return ret0
+}
+
+// Lockout indicates an expected call of Lockout.
+func (mr *MockCachePortMockRecorder) Lockout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lockout", reflect.TypeOf((*MockCachePort)(nil).Lockout))
+}
+
+// Nonce mocks base method.
+func (m *MockCachePort) Nonce() outbound_port.NonceCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nonce")
+	ret0, _ := ret[0].(outbound_port.NonceCachePort)
+	return ret0
+}
+
+// Nonce indicates an expected call of Nonce.
+func (mr *MockCachePortMockRecorder) Nonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockCachePort)(nil).Nonce))
+}
+
+// Ping mocks base method.
+func (m *MockCachePort) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockCachePortMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockCachePort)(nil).Ping), ctx)
+}
+
+// RateLimit mocks base method.
+func (m *MockCachePort) RateLimit() outbound_port.RateLimitCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateLimit")
+	ret0, _ := ret[0].(outbound_port.RateLimitCachePort)
+	return ret0
+}
+
+// RateLimit indicates an expected call of RateLimit.
+func (mr *MockCachePortMockRecorder) RateLimit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateLimit", reflect.TypeOf((*MockCachePort)(nil).RateLimit))
+}
+
+// Token mocks base method.
+func (m *MockCachePort) Token() outbound_port.TokenCachePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(outbound_port.TokenCachePort)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockCachePortMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockCachePort)(nil).Token))
+}
@@ -5,6 +5,7 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	sql "database/sql"
 	outbound_port "go-template/internal/port/outbound"
 	reflect "reflect"
@@ -49,19 +50,61 @@ func (mr *MockDatabasePortMockRecorder) Client() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockDatabasePort)(nil).Client))
 }
 
+// ClientKey mocks base method.
+func (m *MockDatabasePort) ClientKey() outbound_port.ClientKeyDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientKey")
+	ret0, _ := ret[0].(outbound_port.ClientKeyDatabasePort)
+	return ret0
+}
+
+// ClientKey indicates an expected call of ClientKey.
+func (mr *MockDatabasePortMockRecorder) ClientKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientKey", reflect.TypeOf((*MockDatabasePort)(nil).ClientKey))
+}
+
+// ClientScope mocks base method.
+func (m *MockDatabasePort) ClientScope() outbound_port.ClientScopeDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientScope")
+	ret0, _ := ret[0].(outbound_port.ClientScopeDatabasePort)
+	return ret0
+}
+
+// ClientScope indicates an expected call of ClientScope.
+func (mr *MockDatabasePortMockRecorder) ClientScope() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientScope", reflect.TypeOf((*MockDatabasePort)(nil).ClientScope))
+}
+
 // DoInTransaction mocks base method.
-func (m *MockDatabasePort) DoInTransaction(txFunc outbound_port.InTransaction) (interface{}, error) {
+func (m *MockDatabasePort) DoInTransaction(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DoInTransaction", txFunc)
+	ret := m.ctrl.Call(m, "DoInTransaction", ctx, txFunc)
 	ret0, _ := ret[0].(interface{})
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DoInTransaction indicates an expected call of DoInTransaction.
-func (mr *MockDatabasePortMockRecorder) DoInTransaction(txFunc interface{}) *gomock.Call {
+func (mr *MockDatabasePortMockRecorder) DoInTransaction(ctx, txFunc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoInTransaction", reflect.TypeOf((*MockDatabasePort)(nil).DoInTransaction), ctx, txFunc)
+}
+
+// Ping mocks base method.
+func (m *MockDatabasePort) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockDatabasePortMockRecorder) Ping(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoInTransaction", reflect.TypeOf((*MockDatabasePort)(nil).DoInTransaction), txFunc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockDatabasePort)(nil).Ping), ctx)
 }
 
 // MockDatabaseExecutor is a mock of DatabaseExecutor interface.
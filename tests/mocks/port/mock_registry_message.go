@@ -5,6 +5,7 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	outbound_port "go-template/internal/port/outbound"
 	reflect "reflect"
 
@@ -47,3 +48,17 @@ func (mr *MockMessagePortMockRecorder) Client() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockMessagePort)(nil).Client))
 }
+
+// Ping mocks base method.
+func (m *MockMessagePort) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockMessagePortMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockMessagePort)(nil).Ping), ctx)
+}
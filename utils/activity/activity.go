@@ -14,6 +14,7 @@ const (
 	ClientID
 	Payload
 	Result
+	Principal
 )
 
 func NewContext(action string) context.Context {
@@ -22,6 +23,21 @@ func NewContext(action string) context.Context {
 	return context.WithValue(ctx, Action, action)
 }
 
+// NewContextFrom derives an activity context from parent, keeping its
+// transaction ID and trace span so work started upstream stays correlated
+func NewContextFrom(parent context.Context, action string) context.Context {
+	trxID, ok := GetTransactionID(parent)
+	if !ok || trxID == "" {
+		trxID = uuid.New().String()
+	}
+	ctx := context.WithValue(parent, TransactionID, trxID)
+	return context.WithValue(ctx, Action, action)
+}
+
+func WithTransactionID(ctx context.Context, trxID string) context.Context {
+	return context.WithValue(ctx, TransactionID, trxID)
+}
+
 func GetTransactionID(ctx context.Context) (string, bool) {
 	trxID, ok := ctx.Value(TransactionID).(string)
 	return trxID, ok
@@ -45,6 +61,15 @@ func GetClientID(ctx context.Context) (string, bool) {
 	return clientID, ok
 }
 
+// WithPrincipal stores the authenticated caller. It is untyped here, model.PrincipalFromContext reads it back
+func WithPrincipal(ctx context.Context, principal interface{}) context.Context {
+	return context.WithValue(ctx, Principal, principal)
+}
+
+func GetPrincipal(ctx context.Context) interface{} {
+	return ctx.Value(Principal)
+}
+
 func WithPayload(ctx context.Context, payload interface{}) context.Context {
 	return context.WithValue(ctx, Payload, payload)
 }
@@ -0,0 +1,76 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+
+	"go-template/utils"
+)
+
+const (
+	// PrefixLength is the number of leading key characters stored in clear for lookup
+	PrefixLength = 8
+	secretLength = 25
+
+	// MinLength, MaxLength and MinDistinct bound the keys callers may choose themselves
+	MinLength   = 32
+	MaxLength   = 255
+	MinDistinct = 10
+)
+
+// Generate returns a new random plaintext key
+func Generate() string {
+	return utils.GenerateSecureToken(secretLength)
+}
+
+// Prefix returns the public lookup prefix of a plaintext key
+func Prefix(key string) string {
+	if len(key) <= PrefixLength {
+		return key
+	}
+	return key[:PrefixLength]
+}
+
+// ErrPepperMissing is returned by RequirePepper when BEARER_KEY_PEPPER is unset
+var ErrPepperMissing = errors.New("BEARER_KEY_PEPPER is not set")
+
+// RequirePepper fails when BEARER_KEY_PEPPER is empty. Stored hashes only
+// match under the pepper they were made with, so modes that hash keys refuse
+// to start without one instead of silently hashing with an empty key
+func RequirePepper() error {
+	if os.Getenv("BEARER_KEY_PEPPER") == "" {
+		return ErrPepperMissing
+	}
+	return nil
+}
+
+// Hash returns the hex encoded HMAC-SHA256 of key, peppered with BEARER_KEY_PEPPER
+func Hash(key string) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("BEARER_KEY_PEPPER")))
+	mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether key matches hash using a constant-time comparison
+func Verify(key, hash string) bool {
+	return hmac.Equal([]byte(Hash(key)), []byte(hash))
+}
+
+// Strong reports whether a caller supplied key is long enough, printable ASCII
+// without whitespace, and not built from a handful of repeated characters
+func Strong(key string) bool {
+	if len(key) < MinLength || len(key) > MaxLength {
+		return false
+	}
+	distinct := make(map[rune]struct{})
+	for _, r := range key {
+		if r < '!' || r > '~' {
+			return false
+		}
+		distinct[r] = struct{}{}
+	}
+	return len(distinct) >= MinDistinct
+}
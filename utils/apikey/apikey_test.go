@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPepper(t *testing.T) {
+	Convey("Test bearer key pepper", t, func() {
+		Convey("Missing pepper is refused", func() {
+			os.Unsetenv("BEARER_KEY_PEPPER")
+			So(RequirePepper(), ShouldEqual, ErrPepperMissing)
+		})
+
+		Convey("Hashes only verify under the pepper they were made with", func() {
+			os.Setenv("BEARER_KEY_PEPPER", "first-pepper")
+			defer os.Unsetenv("BEARER_KEY_PEPPER")
+			So(RequirePepper(), ShouldBeNil)
+
+			hash := Hash("client-key")
+			So(Verify("client-key", hash), ShouldBeTrue)
+
+			os.Setenv("BEARER_KEY_PEPPER", "second-pepper")
+			So(Verify("client-key", hash), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,191 @@
+package apperror
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	"google.golang.org/grpc/codes"
+)
+
+// Error codes travel with the error through stacktrace.Propagate, so
+// adapters can classify a failure however deep it was raised
+const (
+	CodeValidation stacktrace.ErrorCode = iota + 1
+	CodeNotFound
+	CodeConflict
+	CodeUnauthorized
+	CodeUnavailable
+	CodeForbidden
+	CodeTooManyRequests
+)
+
+// Stable machine readable names of the codes, as returned to callers
+const (
+	Validation   = "validation_error"
+	NotFound     = "not_found"
+	Conflict     = "conflict"
+	Unauthorized = "unauthorized"
+	Unavailable  = "unavailable"
+	Forbidden    = "forbidden"
+	// TooManyRequests is also returned to callers locked out after failed attempts
+	TooManyRequests = "too_many_requests"
+	Internal        = "internal_error"
+)
+
+// kind is how every transport reports one code
+type kind struct {
+	name       string
+	httpStatus int
+	grpcCode   codes.Code
+}
+
+var kinds = map[stacktrace.ErrorCode]kind{
+	CodeValidation:      {Validation, http.StatusBadRequest, codes.InvalidArgument},
+	CodeNotFound:        {NotFound, http.StatusNotFound, codes.NotFound},
+	CodeConflict:        {Conflict, http.StatusConflict, codes.AlreadyExists},
+	CodeUnauthorized:    {Unauthorized, http.StatusUnauthorized, codes.Unauthenticated},
+	CodeUnavailable:     {Unavailable, http.StatusServiceUnavailable, codes.Unavailable},
+	CodeForbidden:       {Forbidden, http.StatusForbidden, codes.PermissionDenied},
+	CodeTooManyRequests: {TooManyRequests, http.StatusTooManyRequests, codes.ResourceExhausted},
+}
+
+var internal = kind{Internal, http.StatusInternalServerError, codes.Internal}
+
+// NewValidation reports input the caller has to fix before trying again
+func NewValidation(msg string, vals ...interface{}) error {
+	return stacktrace.NewMessageWithCode(CodeValidation, msg, vals...)
+}
+
+// ValidationFrom turns an error decoding the caller's input into a validation error
+func ValidationFrom(err error) error {
+	return NewValidation("%s", err)
+}
+
+// FieldError describes one field of the caller's input that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// InvalidFields is the root cause of a validation error with field details
+type InvalidFields []FieldError
+
+func (f InvalidFields) Error() string {
+	messages := make([]string, len(f))
+	for i, field := range f {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewInvalidFields reports the fields the caller has to fix before trying again
+func NewInvalidFields(fields []FieldError) error {
+	return stacktrace.PropagateWithCode(InvalidFields(fields), CodeValidation, "invalid input")
+}
+
+// Details returns the field level details of a validation error, if it has any
+func Details(err error) []FieldError {
+	if stacktrace.GetCode(err) != CodeValidation {
+		return nil
+	}
+	fields, _ := stacktrace.RootCause(err).(InvalidFields)
+	return fields
+}
+
+// NewNotFound reports that the addressed resource does not exist
+func NewNotFound(msg string, vals ...interface{}) error {
+	return stacktrace.NewMessageWithCode(CodeNotFound, msg, vals...)
+}
+
+// NewConflict reports input that clashes with an existing resource
+func NewConflict(msg string, vals ...interface{}) error {
+	return stacktrace.NewMessageWithCode(CodeConflict, msg, vals...)
+}
+
+// NewUnauthorized reports a caller that could not be authenticated
+func NewUnauthorized(msg string, vals ...interface{}) error {
+	return stacktrace.NewMessageWithCode(CodeUnauthorized, msg, vals...)
+}
+
+// NewForbidden reports an authenticated caller that may not do what it asked
+func NewForbidden(msg string, vals ...interface{}) error {
+	return stacktrace.NewMessageWithCode(CodeForbidden, msg, vals...)
+}
+
+// retryLater is the root cause of a too many requests error
+type retryLater time.Duration
+
+func (r retryLater) Error() string {
+	return "Too Many Requests"
+}
+
+// NewTooManyRequests reports a caller that may only try again after retryAfter
+func NewTooManyRequests(retryAfter time.Duration) error {
+	return stacktrace.PropagateWithCode(retryLater(retryAfter), CodeTooManyRequests, "too many requests")
+}
+
+// RetryAfter returns how long the caller of a too many requests error has to
+// wait, zero for any other error
+func RetryAfter(err error) time.Duration {
+	if stacktrace.GetCode(err) != CodeTooManyRequests {
+		return 0
+	}
+	retryAfter, _ := stacktrace.RootCause(err).(retryLater)
+	return time.Duration(retryAfter)
+}
+
+// DependencyCode is the code to propagate a failed database, cache, broker or
+// other dependency call with: CodeUnavailable, unless the adapter already
+// classified the failure, such as a conflict
+//
+//	stacktrace.PropagateWithCode(err, apperror.DependencyCode(err), "upsert client error")
+func DependencyCode(cause error) stacktrace.ErrorCode {
+	if code := stacktrace.GetCode(cause); code != stacktrace.NoCode {
+		return code
+	}
+	return CodeUnavailable
+}
+
+// Code returns the stable name of the error's code, Internal when it has none
+func Code(err error) string {
+	return kindOf(err).name
+}
+
+// HTTPStatus returns the HTTP status the error is reported with
+func HTTPStatus(err error) int {
+	return kindOf(err).httpStatus
+}
+
+// GRPCCode returns the gRPC code the error is reported with
+func GRPCCode(err error) codes.Code {
+	return kindOf(err).grpcCode
+}
+
+func kindOf(err error) kind {
+	if k, ok := kinds[stacktrace.GetCode(err)]; ok {
+		return k
+	}
+	return internal
+}
+
+// Message returns text that is safe to show the caller. Only errors raised
+// for the caller carry their own message; anything else could leak internals
+func Message(err error) string {
+	switch stacktrace.GetCode(err) {
+	case CodeValidation, CodeNotFound, CodeConflict, CodeUnauthorized, CodeForbidden, CodeTooManyRequests:
+		return stacktrace.RootCause(err).Error()
+	case CodeUnavailable:
+		return "service unavailable"
+	default:
+		return "internal server error"
+	}
+}
+
+// Retryable reports whether the same input may succeed later. Only
+// unavailable dependencies qualify; invalid input and bugs fail again
+func Retryable(err error) bool {
+	return stacktrace.GetCode(err) == CodeUnavailable
+}
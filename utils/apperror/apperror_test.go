@@ -0,0 +1,88 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAppError(t *testing.T) {
+	Convey("Test App Error", t, func() {
+		Convey("Codes survive propagation", func() {
+			err := stacktrace.Propagate(NewNotFound("client not found"), "rotate client key error")
+
+			So(Code(err), ShouldEqual, NotFound)
+			So(Message(err), ShouldEqual, "client not found")
+			So(Retryable(err), ShouldBeFalse)
+		})
+
+		Convey("Failed dependencies are unavailable and retryable", func() {
+			err := stacktrace.PropagateWithCode(errors.New("connection refused"), DependencyCode(errors.New("connection refused")), "find client error")
+
+			So(Code(err), ShouldEqual, Unavailable)
+			So(Message(err), ShouldNotContainSubstring, "connection refused")
+			So(Retryable(err), ShouldBeTrue)
+		})
+
+		Convey("A dependency keeps the code its adapter set", func() {
+			cause := NewConflict("certificate_subject is already used by another client")
+			err := stacktrace.PropagateWithCode(cause, DependencyCode(cause), "upsert client error")
+
+			So(Code(err), ShouldEqual, Conflict)
+			So(Message(err), ShouldEqual, "certificate_subject is already used by another client")
+			So(Retryable(err), ShouldBeFalse)
+		})
+
+		Convey("Uncoded errors are internal and never shown", func() {
+			err := stacktrace.Propagate(errors.New("nil pointer"), "seal signing secret error")
+
+			So(Code(err), ShouldEqual, Internal)
+			So(Message(err), ShouldEqual, "internal server error")
+			So(Retryable(err), ShouldBeFalse)
+		})
+
+		Convey("Decoding errors become validation errors verbatim", func() {
+			err := ValidationFrom(errors.New("unexpected 100% EOF"))
+
+			So(Code(err), ShouldEqual, Validation)
+			So(Message(err), ShouldEqual, "unexpected 100% EOF")
+		})
+
+		Convey("Unauthorized", func() {
+			So(Code(NewUnauthorized("bad key")), ShouldEqual, Unauthorized)
+		})
+
+		Convey("Every transport reports a code the same way", func() {
+			err := stacktrace.Propagate(NewForbidden("Forbidden"), "authorize caller error")
+
+			So(HTTPStatus(err), ShouldEqual, http.StatusForbidden)
+			So(GRPCCode(err), ShouldEqual, codes.PermissionDenied)
+			So(HTTPStatus(errors.New("nil pointer")), ShouldEqual, http.StatusInternalServerError)
+			So(GRPCCode(errors.New("nil pointer")), ShouldEqual, codes.Internal)
+		})
+
+		Convey("Too many requests carry how long to wait", func() {
+			err := stacktrace.Propagate(NewTooManyRequests(90*time.Second), "authenticate client error")
+
+			So(Code(err), ShouldEqual, TooManyRequests)
+			So(Message(err), ShouldEqual, "Too Many Requests")
+			So(RetryAfter(err), ShouldEqual, 90*time.Second)
+			So(RetryAfter(NewUnauthorized("Unauthorized")), ShouldEqual, 0)
+		})
+
+		Convey("Field details survive propagation", func() {
+			fields := []FieldError{{Field: "name", Rule: "required", Message: "is required"}}
+			err := stacktrace.Propagate(NewInvalidFields(fields), "upsert client error")
+
+			So(Code(err), ShouldEqual, Validation)
+			So(Message(err), ShouldEqual, "name is required")
+			So(Details(err), ShouldResemble, fields)
+			So(Details(NewValidation("filter is empty")), ShouldBeNil)
+		})
+	})
+}
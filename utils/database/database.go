@@ -13,6 +13,8 @@ import (
 	"go-template/utils/log"
 )
 
+var dbClient *gorm.DB
+
 // InitDatabase initializes the GORM database connection
 func InitDatabase(ctx context.Context, outboundDatabaseDriver string) *gorm.DB {
 	// Get the DSN connection string
@@ -22,6 +24,8 @@ func InitDatabase(ctx context.Context, outboundDatabaseDriver string) *gorm.DB {
 	// Open GORM connection
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Report unique violations as gorm.ErrDuplicatedKey for the adapters
+		TranslateError: true,
 	})
 	if err != nil {
 		log.WithContext(ctx).Error("failed to open database")
@@ -51,5 +55,18 @@ func InitDatabase(ctx context.Context, outboundDatabaseDriver string) *gorm.DB {
 		os.Exit(1)
 	}
 
+	dbClient = db
 	return db
 }
+
+// Close closes the underlying connection pool opened by InitDatabase
+func Close() error {
+	if dbClient == nil {
+		return nil
+	}
+	sqlDB, err := dbClient.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
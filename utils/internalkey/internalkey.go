@@ -0,0 +1,276 @@
+package internalkey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go-template/utils"
+	"go-template/utils/log"
+)
+
+const (
+	// DefaultName names the credential built from the legacy INTERNAL_KEY
+	DefaultName = "default"
+
+	defaultReloadInterval = 30 * time.Second
+)
+
+// Credential is one named caller of the internal routes. It may hold several
+// keys so a new one can be rolled out before the old one is removed
+type Credential struct {
+	Name      string   `json:"name"`
+	KeyHashes []string `json:"key_hashes"`
+	// Routes are full route paths or path.Match patterns, optionally prefixed
+	// with a method as in "GET /internal/v1/clients/:id". Without a method a
+	// route allows every method; "*" allows every route
+	Routes []string `json:"routes"`
+}
+
+// Allows reports whether the credential may call route with method
+func (c Credential) Allows(method, route string) bool {
+	for _, pattern := range c.Routes {
+		if pattern == "*" {
+			return true
+		}
+		if patternMethod, patternRoute, ok := strings.Cut(pattern, " "); ok {
+			if !strings.EqualFold(patternMethod, method) {
+				continue
+			}
+			pattern = strings.TrimSpace(patternRoute)
+		}
+		if pattern == route {
+			return true
+		}
+		if matched, err := path.Match(pattern, route); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash returns the hex encoded SHA-256 of key, as stored in key_hashes
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Store resolves internal keys from INTERNAL_KEYS_FILE, INTERNAL_KEYS and
+// INTERNAL_KEY. Inline keys are parsed once per value. The file is checked at
+// most once per reloadInterval and re-read when it changed on disk, so a key
+// can be rotated without a restart and without touching the other credentials
+type Store struct {
+	reloadInterval time.Duration
+
+	mu        sync.Mutex
+	file      string
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+	fromFile  []Credential
+
+	inline     string
+	fromInline []Credential
+
+	legacyKey  string
+	fromLegacy []Credential
+}
+
+var (
+	sharedStore     *Store
+	sharedStoreOnce sync.Once
+)
+
+// SharedStore returns the process wide store used by the internal auth middleware
+func SharedStore() *Store {
+	sharedStoreOnce.Do(func() {
+		sharedStore = &Store{reloadInterval: reloadInterval()}
+	})
+	return sharedStore
+}
+
+// Match returns the credential owning key. Every configured hash is compared
+// in constant time, so the response time does not reveal which one matched
+func (s *Store) Match(ctx context.Context, key string) (Credential, bool) {
+	presented, _ := hex.DecodeString(Hash(key))
+
+	var (
+		match Credential
+		found bool
+	)
+	for _, credential := range s.Credentials(ctx) {
+		for _, keyHash := range credential.KeyHashes {
+			expected, err := hex.DecodeString(keyHash)
+			if err != nil || len(expected) != len(presented) {
+				continue
+			}
+			if subtle.ConstantTimeCompare(presented, expected) == 1 && !found {
+				match, found = credential, true
+			}
+		}
+	}
+	return match, found
+}
+
+// Credentials returns every configured credential. A file that fails to parse
+// is logged and its previous contents are kept; a file that cannot be read
+// grants nothing. A name used by more than one source grants nothing either,
+// so audit entries always name one credential
+func (s *Store) Credentials(ctx context.Context) []Credential {
+	var credentials []Credential
+
+	fromFile, err := s.loadFile(os.Getenv("INTERNAL_KEYS_FILE"))
+	if err != nil {
+		log.WithContext(ctx).Error("failed to load internal keys file", err)
+	}
+	credentials = append(credentials, fromFile...)
+
+	fromInline, err := s.loadInline(os.Getenv("INTERNAL_KEYS"))
+	if err != nil {
+		log.WithContext(ctx).Error("failed to parse INTERNAL_KEYS", err)
+	}
+	credentials = append(credentials, fromInline...)
+
+	credentials = append(credentials, s.loadLegacy(os.Getenv("INTERNAL_KEY"))...)
+	return withoutSharedNames(ctx, credentials)
+}
+
+// withoutSharedNames drops every credential whose name another source uses
+// too. Parse already rejects a name used twice within one source
+func withoutSharedNames(ctx context.Context, credentials []Credential) []Credential {
+	counts := make(map[string]int, len(credentials))
+	for _, credential := range credentials {
+		counts[credential.Name]++
+	}
+
+	unique := make([]Credential, 0, len(credentials))
+	for _, credential := range credentials {
+		if counts[credential.Name] == 1 {
+			unique = append(unique, credential)
+			continue
+		}
+		if counts[credential.Name] > 0 {
+			log.WithContext(ctx).Error("ambiguous internal key", fmt.Errorf("internal key %q is defined by more than one source", credential.Name))
+			// Reported once per name
+			counts[credential.Name] = 0
+		}
+	}
+	return unique
+}
+
+// Parse reads a JSON list of credentials, rejecting entries without a name
+// or a key and names used twice
+func Parse(data []byte) ([]Credential, error) {
+	var credentials []Credential
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(credentials))
+	for _, credential := range credentials {
+		if credential.Name == "" {
+			return nil, fmt.Errorf("internal key without a name")
+		}
+		if names[credential.Name] {
+			return nil, fmt.Errorf("internal key %q is defined twice", credential.Name)
+		}
+		names[credential.Name] = true
+		if len(credential.KeyHashes) == 0 {
+			return nil, fmt.Errorf("internal key %q has no key_hashes", credential.Name)
+		}
+	}
+	return credentials, nil
+}
+
+func (s *Store) loadFile(file string) ([]Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if file == "" {
+		s.file, s.fromFile = "", nil
+		return nil, nil
+	}
+	if file == s.file && time.Since(s.checkedAt) < s.reloadInterval {
+		return s.fromFile, nil
+	}
+	s.checkedAt = time.Now()
+
+	// A file that is gone or unreadable revokes its keys, deleting it must not
+	// leave the last contents in effect
+	info, err := os.Stat(file)
+	if err != nil {
+		s.file, s.modTime, s.size, s.fromFile = file, time.Time{}, 0, nil
+		return nil, err
+	}
+	if file == s.file && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.fromFile, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		s.file, s.modTime, s.size, s.fromFile = file, time.Time{}, 0, nil
+		return nil, err
+	}
+	credentials, err := Parse(data)
+	if err != nil {
+		return s.fromFile, err
+	}
+
+	s.file, s.modTime, s.size, s.fromFile = file, info.ModTime(), info.Size(), credentials
+	return credentials, nil
+}
+
+// loadInline parses INTERNAL_KEYS when its value changes. A value that fails
+// to parse grants nothing and is reported once
+func (s *Store) loadInline(inline string) ([]Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if inline == s.inline {
+		return s.fromInline, nil
+	}
+
+	s.inline, s.fromInline = inline, nil
+	if inline == "" {
+		return nil, nil
+	}
+	credentials, err := Parse([]byte(inline))
+	if err != nil {
+		return nil, err
+	}
+	s.fromInline = credentials
+	return credentials, nil
+}
+
+// loadLegacy hashes INTERNAL_KEY when its value changes
+func (s *Store) loadLegacy(key string) []Credential {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == s.legacyKey {
+		return s.fromLegacy
+	}
+
+	s.legacyKey, s.fromLegacy = key, nil
+	if key != "" {
+		s.fromLegacy = []Credential{{
+			Name:      DefaultName,
+			KeyHashes: []string{Hash(key)},
+			Routes:    []string{"*"},
+		}}
+	}
+	return s.fromLegacy
+}
+
+// reloadInterval is INTERNAL_KEYS_RELOAD_INTERVAL or 30 seconds
+func reloadInterval() time.Duration {
+	return utils.GetEnvDuration("INTERNAL_KEYS_RELOAD_INTERVAL", defaultReloadInterval)
+}
@@ -0,0 +1,202 @@
+package internalkey
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInternalKey(t *testing.T) {
+	Convey("Test Internal Key", t, func() {
+		ctx := context.Background()
+
+		Convey("Allows", func() {
+			credential := Credential{Routes: []string{"/internal/client-find", "/internal/client-r*"}}
+
+			So(credential.Allows(http.MethodPost, "/internal/client-find"), ShouldBeTrue)
+			So(credential.Allows(http.MethodPost, "/internal/client-rotate"), ShouldBeTrue)
+			So(credential.Allows(http.MethodPost, "/internal/client-revoke"), ShouldBeTrue)
+			So(credential.Allows(http.MethodDelete, "/internal/client-delete"), ShouldBeFalse)
+			So(Credential{Routes: []string{"*"}}.Allows(http.MethodDelete, "/internal/client-delete"), ShouldBeTrue)
+			So(Credential{}.Allows(http.MethodPost, "/internal/client-find"), ShouldBeFalse)
+
+			Convey("Routes with a method only allow that method", func() {
+				reader := Credential{Routes: []string{"GET /internal/v1/clients/:id", "get /internal/v1/clients"}}
+
+				So(reader.Allows(http.MethodGet, "/internal/v1/clients/:id"), ShouldBeTrue)
+				So(reader.Allows(http.MethodGet, "/internal/v1/clients"), ShouldBeTrue)
+				So(reader.Allows(http.MethodPut, "/internal/v1/clients/:id"), ShouldBeFalse)
+				So(reader.Allows(http.MethodDelete, "/internal/v1/clients/:id"), ShouldBeFalse)
+			})
+		})
+
+		Convey("Parse", func() {
+			Convey("Valid list", func() {
+				credentials, err := Parse([]byte(`[{"name":"ops","key_hashes":["` + Hash("k") + `"],"routes":["*"]}]`))
+				So(err, ShouldBeNil)
+				So(credentials, ShouldHaveLength, 1)
+				So(credentials[0].Name, ShouldEqual, "ops")
+			})
+
+			Convey("Missing name", func() {
+				_, err := Parse([]byte(`[{"key_hashes":["x"]}]`))
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Missing key", func() {
+				_, err := Parse([]byte(`[{"name":"ops"}]`))
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Duplicate name", func() {
+				_, err := Parse([]byte(`[{"name":"ops","key_hashes":["x"]},{"name":"ops","key_hashes":["y"]}]`))
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Match", func() {
+			store := &Store{}
+
+			Convey("Legacy INTERNAL_KEY may call every route", func() {
+				os.Setenv("INTERNAL_KEY", "legacy-key")
+				defer os.Unsetenv("INTERNAL_KEY")
+
+				credential, ok := store.Match(ctx, "legacy-key")
+				So(ok, ShouldBeTrue)
+				So(credential.Name, ShouldEqual, DefaultName)
+				So(credential.Allows(http.MethodDelete, "/internal/client-delete"), ShouldBeTrue)
+
+				_, ok = store.Match(ctx, "other-key")
+				So(ok, ShouldBeFalse)
+			})
+
+			Convey("Inline keys", func() {
+				os.Setenv("INTERNAL_KEYS", `[{"name":"billing","key_hashes":["`+Hash("billing-key")+`"],"routes":["/internal/client-find"]}]`)
+				defer os.Unsetenv("INTERNAL_KEYS")
+
+				credential, ok := store.Match(ctx, "billing-key")
+				So(ok, ShouldBeTrue)
+				So(credential.Name, ShouldEqual, "billing")
+			})
+
+			Convey("Rotating one key in the file leaves the others alone", func() {
+				file := filepath.Join(t.TempDir(), "internal-keys.json")
+				write := func(aliceKey string, modTime time.Time) {
+					content := `[
+						{"name":"alice","key_hashes":["` + Hash(aliceKey) + `"],"routes":["*"]},
+						{"name":"deployer","key_hashes":["` + Hash("deployer-key") + `"],"routes":["/internal/client-upsert"]}
+					]`
+					So(os.WriteFile(file, []byte(content), 0o600), ShouldBeNil)
+					So(os.Chtimes(file, modTime, modTime), ShouldBeNil)
+				}
+				os.Setenv("INTERNAL_KEYS_FILE", file)
+				defer os.Unsetenv("INTERNAL_KEYS_FILE")
+
+				write("alice-old", time.Now().Add(-time.Hour))
+				_, ok := store.Match(ctx, "alice-old")
+				So(ok, ShouldBeTrue)
+
+				write("alice-new", time.Now())
+				_, ok = store.Match(ctx, "alice-old")
+				So(ok, ShouldBeFalse)
+				credential, ok := store.Match(ctx, "alice-new")
+				So(ok, ShouldBeTrue)
+				So(credential.Name, ShouldEqual, "alice")
+				credential, ok = store.Match(ctx, "deployer-key")
+				So(ok, ShouldBeTrue)
+				So(credential.Name, ShouldEqual, "deployer")
+			})
+
+			Convey("The file is checked at most once per reload interval", func() {
+				store.reloadInterval = time.Hour
+				file := filepath.Join(t.TempDir(), "internal-keys.json")
+				write := func(key string, modTime time.Time) {
+					So(os.WriteFile(file, []byte(`[{"name":"alice","key_hashes":["`+Hash(key)+`"],"routes":["*"]}]`), 0o600), ShouldBeNil)
+					So(os.Chtimes(file, modTime, modTime), ShouldBeNil)
+				}
+				os.Setenv("INTERNAL_KEYS_FILE", file)
+				defer os.Unsetenv("INTERNAL_KEYS_FILE")
+
+				write("alice-old", time.Now().Add(-time.Hour))
+				_, ok := store.Match(ctx, "alice-old")
+				So(ok, ShouldBeTrue)
+
+				write("alice-new", time.Now())
+				_, ok = store.Match(ctx, "alice-old")
+				So(ok, ShouldBeTrue)
+
+				store.checkedAt = time.Now().Add(-time.Hour)
+				_, ok = store.Match(ctx, "alice-new")
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("Inline keys are parsed once per value", func() {
+				os.Setenv("INTERNAL_KEYS", `[{"name":"billing","key_hashes":["`+Hash("billing-key")+`"],"routes":["*"]}]`)
+				defer os.Unsetenv("INTERNAL_KEYS")
+
+				first := store.Credentials(ctx)
+				second := store.Credentials(ctx)
+				So(second, ShouldHaveLength, 1)
+				So(&second[0].KeyHashes[0], ShouldEqual, &first[0].KeyHashes[0])
+
+				os.Setenv("INTERNAL_KEYS", `[{"name":"support","key_hashes":["`+Hash("support-key")+`"],"routes":["*"]}]`)
+				credential, ok := store.Match(ctx, "support-key")
+				So(ok, ShouldBeTrue)
+				So(credential.Name, ShouldEqual, "support")
+			})
+
+			Convey("A broken file keeps the previous keys", func() {
+				file := filepath.Join(t.TempDir(), "internal-keys.json")
+				So(os.WriteFile(file, []byte(`[{"name":"alice","key_hashes":["`+Hash("alice-key")+`"],"routes":["*"]}]`), 0o600), ShouldBeNil)
+				os.Setenv("INTERNAL_KEYS_FILE", file)
+				defer os.Unsetenv("INTERNAL_KEYS_FILE")
+
+				_, ok := store.Match(ctx, "alice-key")
+				So(ok, ShouldBeTrue)
+
+				So(os.WriteFile(file, []byte(`[{"name":`), 0o600), ShouldBeNil)
+				later := time.Now().Add(time.Minute)
+				So(os.Chtimes(file, later, later), ShouldBeNil)
+				_, ok = store.Match(ctx, "alice-key")
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("A deleted file revokes its keys", func() {
+				file := filepath.Join(t.TempDir(), "internal-keys.json")
+				So(os.WriteFile(file, []byte(`[{"name":"alice","key_hashes":["`+Hash("alice-key")+`"],"routes":["*"]}]`), 0o600), ShouldBeNil)
+				os.Setenv("INTERNAL_KEYS_FILE", file)
+				defer os.Unsetenv("INTERNAL_KEYS_FILE")
+
+				_, ok := store.Match(ctx, "alice-key")
+				So(ok, ShouldBeTrue)
+
+				So(os.Remove(file), ShouldBeNil)
+				_, ok = store.Match(ctx, "alice-key")
+				So(ok, ShouldBeFalse)
+
+				So(os.WriteFile(file, []byte(`[{"name":"alice","key_hashes":["`+Hash("alice-key")+`"],"routes":["*"]}]`), 0o600), ShouldBeNil)
+				_, ok = store.Match(ctx, "alice-key")
+				So(ok, ShouldBeTrue)
+			})
+
+			Convey("A name used by more than one source grants nothing", func() {
+				os.Setenv("INTERNAL_KEYS", `[{"name":"default","key_hashes":["`+Hash("inline-key")+`"],"routes":["*"]},{"name":"billing","key_hashes":["`+Hash("billing-key")+`"],"routes":["*"]}]`)
+				defer os.Unsetenv("INTERNAL_KEYS")
+				os.Setenv("INTERNAL_KEY", "legacy-key")
+				defer os.Unsetenv("INTERNAL_KEY")
+
+				_, ok := store.Match(ctx, "inline-key")
+				So(ok, ShouldBeFalse)
+				_, ok = store.Match(ctx, "legacy-key")
+				So(ok, ShouldBeFalse)
+				_, ok = store.Match(ctx, "billing-key")
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is the RFC 7662 introspection response. Claims keeps the raw body,
+// including provider specific members
+type Result struct {
+	Active   bool   `json:"active"`
+	Scope    string `json:"scope"`
+	ClientID string `json:"client_id"`
+	Username string `json:"username"`
+	Subject  string `json:"sub"`
+	Issuer   string `json:"iss"`
+	// ExpiresAt is a NumericDate, which may carry a fraction of a second
+	ExpiresAt float64                `json:"exp"`
+	Claims    map[string]interface{} `json:"-"`
+}
+
+// Scopes splits the space delimited scope member
+func (r Result) Scopes() []string {
+	return strings.Fields(r.Scope)
+}
+
+// Principal returns the subject, falling back to the client ID and then the
+// username for client credentials tokens that carry no `sub`
+func (r Result) Principal() string {
+	for _, value := range []string{r.Subject, r.ClientID, r.Username} {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Expiry returns the `exp` member as a time, or the zero time when it is missing
+func (r Result) Expiry() time.Time {
+	if r.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(r.ExpiresAt), 0)
+}
+
+// Client calls an introspection endpoint, authenticating with client credentials
+// (client_secret_basic)
+type Client struct {
+	endpoint     string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+}
+
+var (
+	clients   = map[string]*Client{}
+	clientsMu sync.Mutex
+)
+
+// NewClient creates an introspection client for the endpoint
+func NewClient(endpoint, clientID, clientSecret string) *Client {
+	return &Client{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// SharedClient returns one client per endpoint and credentials, so connections are reused
+func SharedClient(endpoint, clientID, clientSecret string) *Client {
+	key := endpoint + "\x00" + clientID + "\x00" + clientSecret
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if client, ok := clients[key]; ok {
+		return client
+	}
+	client := NewClient(endpoint, clientID, clientSecret)
+	clients[key] = client
+	return client
+}
+
+// Introspect asks the endpoint whether the token is active
+func (c *Client) Introspect(ctx context.Context, token string) (Result, error) {
+	if c.endpoint == "" {
+		return Result{}, fmt.Errorf("introspection endpoint is not configured")
+	}
+
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to introspect token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return Result{}, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	// Round trip through JSON to fill the typed members from the raw body
+	body, _ := json.Marshal(claims)
+	var result Result
+	if err := json.Unmarshal(body, &result); err != nil {
+		return Result{}, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+	result.Claims = claims
+
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIntrospect(t *testing.T) {
+	Convey("Test Introspect", t, func() {
+		exp := time.Now().Add(time.Hour).Unix()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, secret, ok := r.BasicAuth()
+			if !ok || id != "go-template" || secret != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Method != http.MethodPost || r.FormValue("token_type_hint") != "access_token" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			switch r.FormValue("token") {
+			case "active-token":
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"active":    true,
+					"scope":     "clients:read clients:write",
+					"client_id": "billing",
+					"iss":       "https://idp.example.com/",
+					"exp":       exp,
+					"tenant":    "acme",
+				})
+			case "fractional-exp-token":
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"active": true,
+					"exp":    float64(exp) + 0.75,
+				})
+			default:
+				json.NewEncoder(w).Encode(map[string]interface{}{"active": false})
+			}
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		client := NewClient(server.URL, "go-template", "s3cret")
+
+		Convey("Active token", func() {
+			result, err := client.Introspect(ctx, "active-token")
+			So(err, ShouldBeNil)
+			So(result.Active, ShouldBeTrue)
+			So(result.Scopes(), ShouldResemble, []string{"clients:read", "clients:write"})
+			So(result.Principal(), ShouldEqual, "billing")
+			So(result.Issuer, ShouldEqual, "https://idp.example.com/")
+			So(result.Expiry().Unix(), ShouldEqual, exp)
+			So(result.Claims["tenant"], ShouldEqual, "acme")
+		})
+
+		Convey("Fractional exp is truncated to the second", func() {
+			result, err := client.Introspect(ctx, "fractional-exp-token")
+			So(err, ShouldBeNil)
+			So(result.Expiry().Unix(), ShouldEqual, exp)
+		})
+
+		Convey("Inactive token", func() {
+			result, err := client.Introspect(ctx, "unknown-token")
+			So(err, ShouldBeNil)
+			So(result.Active, ShouldBeFalse)
+			So(result.Expiry().IsZero(), ShouldBeTrue)
+		})
+
+		Convey("Wrong client credentials", func() {
+			_, err := NewClient(server.URL, "go-template", "wrong").Introspect(ctx, "active-token")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Endpoint not configured", func() {
+			_, err := NewClient("", "", "").Introspect(ctx, "active-token")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Shared client is reused", func() {
+			So(SharedClient(server.URL, "a", "b"), ShouldEqual, SharedClient(server.URL, "a", "b"))
+			So(SharedClient(server.URL, "a", "b"), ShouldNotEqual, SharedClient(server.URL, "a", "c"))
+		})
+	})
+}
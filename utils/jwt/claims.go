@@ -0,0 +1,32 @@
+package jwt
+
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Scopes collects the permissions granted by the token: the space delimited
+// `scope` claim (RFC 8693), `scp` as a string or list, and `groups`
+func Scopes(claims jwt.MapClaims) []string {
+	var scopes []string
+	for _, name := range []string{"scope", "scp", "groups"} {
+		switch value := claims[name].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(value)...)
+		case []interface{}:
+			for _, item := range value {
+				if scope, ok := item.(string); ok && scope != "" {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// Subject returns the `sub` claim, or an empty string when it is missing
+func Subject(claims jwt.MapClaims) string {
+	subject, _ := claims["sub"].(string)
+	return subject
+}
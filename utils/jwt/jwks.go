@@ -0,0 +1,229 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultJWKSTTL applies when the endpoint sends no usable Cache-Control max-age
+	defaultJWKSTTL = 5 * time.Minute
+	minJWKSTTL     = 30 * time.Second
+	maxJWKSTTL     = 24 * time.Hour
+	// minJWKSRefetch rate limits refetches triggered by an unknown kid, failed or not
+	minJWKSRefetch = 30 * time.Second
+	// jwksRetryInterval is how soon a failed background refresh is retried
+	jwksRetryInterval = 30 * time.Second
+	jwksFetchTimeout  = 10 * time.Second
+)
+
+// ErrUnknownKid is returned when no key in the set matches the token's kid
+var ErrUnknownKid = errors.New("key with kid not found in JWKS")
+
+var jwksCaches sync.Map
+
+// JWKSCache keeps the last good key set of one JWKS endpoint, safe for concurrent use
+type JWKSCache struct {
+	client *JWKSClient
+
+	defaultTTL    time.Duration
+	minRefetch    time.Duration
+	retryInterval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	expiresAt time.Time
+	fetchedAt time.Time
+	// attemptedAt also moves on failed fetches, so an endpoint that is down
+	// is not asked again for every token with an unknown kid
+	attemptedAt time.Time
+
+	// fetchMu makes concurrent callers share one request instead of stampeding the endpoint
+	fetchMu sync.Mutex
+	start   sync.Once
+}
+
+// SharedJWKSCache returns the process wide cache for jwksURL, creating it on first use
+func SharedJWKSCache(jwksURL string) *JWKSCache {
+	if cache, ok := jwksCaches.Load(jwksURL); ok {
+		return cache.(*JWKSCache)
+	}
+	cache, _ := jwksCaches.LoadOrStore(jwksURL, NewJWKSCache(jwksURL))
+	return cache.(*JWKSCache)
+}
+
+// NewJWKSCache creates a cache for jwksURL; most callers want SharedJWKSCache
+func NewJWKSCache(jwksURL string) *JWKSCache {
+	return &JWKSCache{
+		client:        NewJWKSClient(jwksURL),
+		defaultTTL:    defaultJWKSTTL,
+		minRefetch:    minJWKSRefetch,
+		retryInterval: jwksRetryInterval,
+	}
+}
+
+// Key returns the public key for kid, fetching the set on first use and
+// refetching it when kid is unknown, at most once per minRefetch whether the
+// last attempt succeeded or not
+func (c *JWKSCache) Key(ctx context.Context, kid string) (interface{}, error) {
+	c.start.Do(func() {
+		go c.refreshLoop()
+	})
+
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+
+	c.mu.RLock()
+	attemptedAt := c.attemptedAt
+	c.mu.RUnlock()
+
+	if attemptedAt.IsZero() || time.Since(attemptedAt) >= c.minRefetch {
+		// Keep serving the last good set if the refetch fails
+		if err := c.refresh(ctx, attemptedAt); err != nil && !c.loaded() {
+			return nil, err
+		}
+		if key, ok := c.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKid, kid)
+}
+
+// loaded reports whether a key set was ever fetched
+func (c *JWKSCache) loaded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.fetchedAt.IsZero()
+}
+
+func (c *JWKSCache) lookup(kid string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the set unless another caller already tried after seen
+func (c *JWKSCache) refresh(ctx context.Context, seen time.Time) error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	c.mu.Lock()
+	if c.attemptedAt.After(seen) {
+		c.mu.Unlock()
+		return nil
+	}
+	c.attemptedAt = time.Now()
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	jwkSet, header, err := c.client.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(jwkSet.Keys))
+	for _, jwk := range jwkSet.Keys {
+		// Skip keys we cannot use instead of rejecting the whole set
+		key, err := jwk.GetPublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	c.keys = keys
+	c.fetchedAt = now
+	c.expiresAt = now.Add(cacheTTL(header, c.defaultTTL))
+	c.mu.Unlock()
+
+	return nil
+}
+
+// refreshLoop renews the set shortly before it expires so requests never wait on the endpoint
+func (c *JWKSCache) refreshLoop() {
+	for {
+		c.mu.RLock()
+		wait := time.Until(c.expiresAt)
+		fetchedAt := c.fetchedAt
+		c.mu.RUnlock()
+
+		if fetchedAt.IsZero() {
+			wait = c.retryInterval
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		c.mu.RLock()
+		attemptedAt := c.attemptedAt
+		c.mu.RUnlock()
+		if err := c.refresh(context.Background(), attemptedAt); err != nil {
+			time.Sleep(c.retryInterval)
+		}
+	}
+}
+
+// cacheTTL reads max-age from Cache-Control, clamped so a bad header cannot
+// make us hammer the endpoint or hold on to keys for days
+func cacheTTL(header http.Header, fallback time.Duration) time.Duration {
+	ttl := fallback
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return minJWKSTTL
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	if ttl < minJWKSTTL {
+		return minJWKSTTL
+	}
+	if ttl > maxJWKSTTL {
+		return maxJWKSTTL
+	}
+	return ttl
+}
+
+// fetch downloads the key set and returns the response headers for caching
+func (c *JWKSClient) fetch(ctx context.Context) (*JWKSet, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.jwksURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var jwkSet JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	return &jwkSet, resp.Header, nil
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func rsaJWK(kid string, key *rsa.PrivateKey) JWK {
+	return JWK{
+		Kid: kid,
+		Kty: "RSA",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []JWK
+	down     bool
+	requests int32
+}
+
+func newJWKSServer(cacheControl string, keys ...JWK) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", cacheControl)
+		json.NewEncoder(w).Encode(JWKSet{Keys: s.keys})
+	}))
+	return s
+}
+
+func (s *jwksServer) set(down bool, keys ...JWK) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+	if keys != nil {
+		s.keys = keys
+	}
+}
+
+func TestJWKSCache(t *testing.T) {
+	Convey("Test JWKS Cache", t, func() {
+		key1, _ := rsa.GenerateKey(rand.Reader, 2048)
+		key2, _ := rsa.GenerateKey(rand.Reader, 2048)
+		server := newJWKSServer("public, max-age=600", rsaJWK("kid-1", key1))
+		defer server.Close()
+
+		cache := NewJWKSCache(server.URL)
+		ctx := context.Background()
+
+		Convey("Key set is fetched once and shared by concurrent callers", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, _ = cache.Key(ctx, "kid-1")
+				}()
+			}
+			wg.Wait()
+
+			key, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+			So(key.(*rsa.PublicKey).N.Cmp(key1.N), ShouldEqual, 0)
+			So(atomic.LoadInt32(&server.requests), ShouldEqual, 1)
+		})
+
+		Convey("Cache-Control max-age sets the expiry", func() {
+			_, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+
+			cache.mu.RLock()
+			ttl := cache.expiresAt.Sub(cache.fetchedAt)
+			cache.mu.RUnlock()
+			So(ttl, ShouldEqual, 10*time.Minute)
+		})
+
+		Convey("Unknown kid refetches, rate limited", func() {
+			_, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+			server.set(false, rsaJWK("kid-1", key1), rsaJWK("kid-2", key2))
+
+			_, err = cache.Key(ctx, "kid-2")
+			So(err, ShouldWrap, ErrUnknownKid)
+			So(atomic.LoadInt32(&server.requests), ShouldEqual, 1)
+
+			cache.minRefetch = 0
+			key, err := cache.Key(ctx, "kid-2")
+			So(err, ShouldBeNil)
+			So(key.(*rsa.PublicKey).N.Cmp(key2.N), ShouldEqual, 0)
+			So(atomic.LoadInt32(&server.requests), ShouldEqual, 2)
+		})
+
+		Convey("Last good key set is served while the endpoint is down", func() {
+			_, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+			server.set(true)
+			cache.minRefetch = 0
+
+			_, err = cache.Key(ctx, "kid-2")
+			So(err, ShouldWrap, ErrUnknownKid)
+
+			key, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+			So(key, ShouldNotBeNil)
+		})
+
+		Convey("First fetch failing is an error", func() {
+			server.set(true)
+
+			_, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Failed fetches rate limit unknown kid refetches too", func() {
+			server.set(true)
+
+			_, err := cache.Key(ctx, "kid-1")
+			So(err, ShouldNotBeNil)
+
+			for i := 0; i < 10; i++ {
+				_, err = cache.Key(ctx, "random-kid")
+				So(err, ShouldWrap, ErrUnknownKid)
+			}
+			So(atomic.LoadInt32(&server.requests), ShouldEqual, 1)
+		})
+
+		Convey("Claims are validated with the shared cache", func() {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"sub":   "service-account",
+				"scope": "clients:read clients:write",
+				"exp":   time.Now().Add(time.Minute).Unix(),
+			})
+			token.Header["kid"] = "kid-1"
+			signed, err := token.SignedString(key1)
+			So(err, ShouldBeNil)
+
+			for i := 0; i < 3; i++ {
+				claims, err := GetJWTClaimsWithURL(signed, server.URL)
+				So(err, ShouldBeNil)
+				So(Subject(claims), ShouldEqual, "service-account")
+				So(Scopes(claims), ShouldResemble, []string{"clients:read", "clients:write"})
+			}
+			So(atomic.LoadInt32(&server.requests), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestCacheTTL(t *testing.T) {
+	Convey("Test Cache-Control TTL", t, func() {
+		header := func(value string) http.Header {
+			h := http.Header{}
+			h.Set("Cache-Control", value)
+			return h
+		}
+
+		So(cacheTTL(http.Header{}, defaultJWKSTTL), ShouldEqual, defaultJWKSTTL)
+		So(cacheTTL(header("public, max-age=3600"), defaultJWKSTTL), ShouldEqual, time.Hour)
+		So(cacheTTL(header("max-age=1"), defaultJWKSTTL), ShouldEqual, minJWKSTTL)
+		So(cacheTTL(header("max-age=31536000"), defaultJWKSTTL), ShouldEqual, maxJWKSTTL)
+		So(cacheTTL(header("no-store"), defaultJWKSTTL), ShouldEqual, minJWKSTTL)
+	})
+}
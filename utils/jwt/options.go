@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultLeeway = 30 * time.Second
+
+// defaultAlgorithms are the asymmetric algorithms GetPublicKey can produce keys for
+var defaultAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}
+
+// ValidationOptions describes what a token must satisfy besides a valid signature.
+// Empty Issuers or Audiences skip that check
+type ValidationOptions struct {
+	Issuers    []string
+	Audiences  []string
+	Algorithms []string
+	Leeway     time.Duration
+}
+
+// OptionsFromEnv reads AUTH_JWT_ISSUERS, AUTH_JWT_AUDIENCES, AUTH_JWT_ALGORITHMS
+// (comma separated) and AUTH_JWT_LEEWAY
+func OptionsFromEnv() ValidationOptions {
+	options := ValidationOptions{
+		Issuers:    splitList(os.Getenv("AUTH_JWT_ISSUERS")),
+		Audiences:  splitList(os.Getenv("AUTH_JWT_AUDIENCES")),
+		Algorithms: splitList(os.Getenv("AUTH_JWT_ALGORITHMS")),
+		Leeway:     defaultLeeway,
+	}
+	if len(options.Algorithms) == 0 {
+		options.Algorithms = defaultAlgorithms
+	}
+	if leeway, err := time.ParseDuration(os.Getenv("AUTH_JWT_LEEWAY")); err == nil && leeway >= 0 {
+		options.Leeway = leeway
+	}
+	return options
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsAny(values []string, expected []string) bool {
+	for _, value := range values {
+		for _, want := range expected {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
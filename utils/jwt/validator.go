@@ -2,9 +2,13 @@ package jwt
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -18,13 +22,18 @@ type JWKSet struct {
 	Keys []JWK `json:"keys"`
 }
 
-// JWK represents a JSON Web Key
+// JWK represents a JSON Web Key. RSA keys use N and E, EC keys Crv, X and Y,
+// and OKP keys Crv and X
 type JWK struct {
 	Kid string `json:"kid"`
 	Kty string `json:"kty"`
 	Use string `json:"use"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
 }
 
 // JWKSClient handles fetching and caching JWKS
@@ -45,35 +54,25 @@ func NewJWKSClient(jwksURL string) *JWKSClient {
 
 // GetJWKSet fetches the JWKS from the URL
 func (c *JWKSClient) GetJWKSet(ctx context.Context) (*JWKSet, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.jwksURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
-	}
-
-	var jwkSet JWKSet
-	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
-		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
-	}
-
-	return &jwkSet, nil
+	jwkSet, _, err := c.fetch(ctx)
+	return jwkSet, err
 }
 
-// GetPublicKey converts JWK to RSA public key
-func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
-	if jwk.Kty != "RSA" {
+// GetPublicKey converts JWK to an RSA, ECDSA (P-256, P-384) or Ed25519 public key
+func (jwk *JWK) GetPublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		return jwk.rsaPublicKey()
+	case "EC":
+		return jwk.ecdsaPublicKey()
+	case "OKP":
+		return jwk.ed25519PublicKey()
+	default:
 		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
 	}
+}
 
+func (jwk *JWK) rsaPublicKey() (*rsa.PublicKey, error) {
 	// Decode the modulus
 	nBytes, err := base64.RawURLEncoding.DecodeString(jwk.N)
 	if err != nil {
@@ -103,117 +102,123 @@ func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
 	}, nil
 }
 
+func (jwk *JWK) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var (
+		curve     elliptic.Curve
+		ecdhCurve ecdh.Curve
+	)
+	switch jwk.Crv {
+	case "P-256":
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(xBytes) != size {
+		return nil, fmt.Errorf("invalid x coordinate")
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil || len(yBytes) != size {
+		return nil, fmt.Errorf("invalid y coordinate")
+	}
+
+	// Reject points that are not on the curve
+	point := append(append([]byte{4}, xBytes...), yBytes...)
+	if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid curve point: %w", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
+
+func (jwk *JWK) ed25519PublicKey() (ed25519.PublicKey, error) {
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(xBytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key")
+	}
+
+	return ed25519.PublicKey(xBytes), nil
+}
+
 // ValidateJWTWithURL validates the JWT token with a specific JWKS URL
 // Returns (isValid bool, error)
 func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
-	// Create JWKS client
-	jwksClient := NewJWKSClient(jwksURL)
+	_, err := GetJWTClaimsWithURL(tokenString, jwksURL)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	// Parse the token to get the header and validate
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+// GetJWTClaimsWithURL validates JWT with specific URL and returns the claims map.
+// Issuer, audience, algorithms and leeway come from OptionsFromEnv
+func GetJWTClaimsWithURL(tokenString, jwksURL string) (jwt.MapClaims, error) {
+	return GetJWTClaimsWithOptions(tokenString, jwksURL, OptionsFromEnv())
+}
+
+// GetJWTClaimsWithOptions validates JWT against the keys at jwksURL and the given options.
+// Keys come from the shared JWKS cache, so the endpoint is not hit per request
+func GetJWTClaimsWithOptions(tokenString, jwksURL string, options ValidationOptions) (jwt.MapClaims, error) {
+	jwksCache := SharedJWKSCache(jwksURL)
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(options.Algorithms),
+		jwt.WithLeeway(options.Leeway),
+		jwt.WithExpirationRequired(),
+	)
 
+	// Parse the token to get the header and validate
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Get the kid from the token header
 		kid, ok := token.Header["kid"].(string)
 		if !ok {
 			return nil, fmt.Errorf("kid not found in token header")
 		}
 
-		// Fetch JWKS
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		jwkSet, err := jwksClient.GetJWKSet(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
-		}
-
-		// Find the matching key
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
-		}
-
-		return nil, fmt.Errorf("key with kid %s not found in JWKS", kid)
+		// The signing method checks the key type, so an RSA key cannot verify an ES256 token
+		return jwksCache.Key(ctx, kid)
 	})
 
 	if err != nil {
-		return false, fmt.Errorf("failed to parse/validate token: %w", err)
+		return nil, fmt.Errorf("failed to parse/validate token: %w", err)
 	}
 
 	if !token.Valid {
-		return false, fmt.Errorf("token is not valid")
+		return nil, fmt.Errorf("token is not valid")
 	}
 
-	// Extract claims to check expiration
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return false, fmt.Errorf("failed to parse claims")
-	}
-
-	// Check token expiration
-	if exp, ok := claims["exp"].(float64); ok {
-		expTime := time.Unix(int64(exp), 0)
-		if time.Now().After(expTime) {
-			return false, fmt.Errorf("token has expired at %s", expTime.Format(time.RFC3339))
-		}
-	} else {
-		return false, fmt.Errorf("token does not contain expiration claim")
+		return nil, fmt.Errorf("failed to parse claims")
 	}
 
-	// Check not before time if present
-	if nbf, ok := claims["nbf"].(float64); ok {
-		nbfTime := time.Unix(int64(nbf), 0)
-		if time.Now().Before(nbfTime) {
-			return false, fmt.Errorf("token is not valid before %s", nbfTime.Format(time.RFC3339))
+	if len(options.Issuers) > 0 {
+		issuer, err := claims.GetIssuer()
+		if err != nil || !containsAny([]string{issuer}, options.Issuers) {
+			return nil, fmt.Errorf("unexpected token issuer: %q", issuer)
 		}
 	}
 
-	return true, nil
-}
-
-// GetJWTClaimsWithURL validates JWT with specific URL and returns the claims map
-func GetJWTClaimsWithURL(tokenString, jwksURL string) (jwt.MapClaims, error) {
-	// First validate the token
-	isValid, err := ValidateJWTWithURL(tokenString, jwksURL)
-	if !isValid && err != nil {
-		return nil, err
-	}
-
-	// Parse token again to get claims (we know it's valid now)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// We already validated, so just get the key again
-		jwksClient := NewJWKSClient(jwksURL)
-		kid := token.Header["kid"].(string)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		jwkSet, jwkErr := jwksClient.GetJWKSet(ctx)
-		if jwkErr != nil {
-			return nil, jwkErr
-		}
-
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
+	if len(options.Audiences) > 0 {
+		audience, err := claims.GetAudience()
+		if err != nil || !containsAny(audience, options.Audiences) {
+			return nil, fmt.Errorf("token audience does not match: %v", []string(audience))
 		}
-
-		return nil, fmt.Errorf("key not found")
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, fmt.Errorf("failed to parse claims")
 	}
 
 	return claims, nil
@@ -0,0 +1,239 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func ecJWK(kid, crv string, key *ecdsa.PrivateKey) JWK {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return JWK{
+		Kid: kid,
+		Kty: "EC",
+		Crv: crv,
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func okpJWK(kid string, key ed25519.PublicKey) JWK {
+	return JWK{
+		Kid: kid,
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(key),
+	}
+}
+
+func signToken(method jwt.SigningMethod, kid string, key crypto.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	So(err, ShouldBeNil)
+	return signed
+}
+
+func TestGetPublicKey(t *testing.T) {
+	Convey("Test JWK Public Key", t, func() {
+		Convey("EC keys on P-256 and P-384", func() {
+			for crv, curve := range map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384()} {
+				private, _ := ecdsa.GenerateKey(curve, rand.Reader)
+				jwk := ecJWK("ec", crv, private)
+
+				key, err := jwk.GetPublicKey()
+				So(err, ShouldBeNil)
+				So(key.(*ecdsa.PublicKey).Equal(&private.PublicKey), ShouldBeTrue)
+			}
+		})
+
+		Convey("EC point off the curve is rejected", func() {
+			private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			jwk := ecJWK("ec", "P-256", private)
+			jwk.Y = jwk.X
+
+			_, err := jwk.GetPublicKey()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Unsupported curve is rejected", func() {
+			private, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+			jwk := ecJWK("ec", "P-521", private)
+
+			_, err := jwk.GetPublicKey()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("OKP Ed25519 key", func() {
+			public, _, _ := ed25519.GenerateKey(rand.Reader)
+			jwk := okpJWK("ed", public)
+
+			key, err := jwk.GetPublicKey()
+			So(err, ShouldBeNil)
+			So(key.(ed25519.PublicKey).Equal(public), ShouldBeTrue)
+		})
+
+		Convey("Unsupported key type is rejected", func() {
+			jwk := JWK{Kty: "oct"}
+
+			_, err := jwk.GetPublicKey()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetJWTClaimsWithOptions(t *testing.T) {
+	Convey("Test JWT Validation Options", t, func() {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+		server := newJWKSServer("max-age=600",
+			rsaJWK("rsa", rsaKey),
+			ecJWK("ec", "P-256", ecKey),
+			okpJWK("ed", edPublic),
+		)
+		defer server.Close()
+
+		options := ValidationOptions{
+			Issuers:    []string{"https://auth.example.com/application/o/go-template/"},
+			Audiences:  []string{"go-template"},
+			Algorithms: defaultAlgorithms,
+			Leeway:     30 * time.Second,
+		}
+		claims := func() jwt.MapClaims {
+			return jwt.MapClaims{
+				"sub": "service-account",
+				"iss": "https://auth.example.com/application/o/go-template/",
+				"aud": []string{"go-template"},
+				"exp": time.Now().Add(time.Minute).Unix(),
+			}
+		}
+
+		Convey("RSA, EC and EdDSA tokens are accepted", func() {
+			tokens := []string{
+				signToken(jwt.SigningMethodRS256, "rsa", rsaKey, claims()),
+				signToken(jwt.SigningMethodES256, "ec", ecKey, claims()),
+				signToken(jwt.SigningMethodEdDSA, "ed", edPrivate, claims()),
+			}
+			for _, token := range tokens {
+				result, err := GetJWTClaimsWithOptions(token, server.URL, options)
+				So(err, ShouldBeNil)
+				So(Subject(result), ShouldEqual, "service-account")
+			}
+		})
+
+		Convey("Token from another issuer is rejected", func() {
+			c := claims()
+			c["iss"] = "https://auth.example.com/application/o/other-app/"
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Token for another audience is rejected", func() {
+			c := claims()
+			c["aud"] = "other-app"
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Issuer and audience are skipped when not configured", func() {
+			c := claims()
+			delete(c, "iss")
+			delete(c, "aud")
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, ValidationOptions{Algorithms: defaultAlgorithms})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Algorithm outside the allowed list is rejected", func() {
+			token := signToken(jwt.SigningMethodES256, "ec", ecKey, claims())
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, ValidationOptions{
+				Algorithms: []string{"RS256"},
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Key of another type cannot verify the token", func() {
+			token := signToken(jwt.SigningMethodES256, "rsa", ecKey, claims())
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Expiry within the leeway is accepted", func() {
+			c := claims()
+			c["exp"] = time.Now().Add(-10 * time.Second).Unix()
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldBeNil)
+
+			options.Leeway = 0
+			_, err = GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Not before within the leeway is accepted", func() {
+			c := claims()
+			c["nbf"] = time.Now().Add(10 * time.Second).Unix()
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldBeNil)
+
+			options.Leeway = 0
+			_, err = GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Token without expiry is rejected", func() {
+			c := claims()
+			delete(c, "exp")
+			token := signToken(jwt.SigningMethodRS256, "rsa", rsaKey, c)
+
+			_, err := GetJWTClaimsWithOptions(token, server.URL, options)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	Convey("Test Options From Env", t, func() {
+		Convey("Defaults", func() {
+			options := OptionsFromEnv()
+			So(options.Issuers, ShouldBeEmpty)
+			So(options.Audiences, ShouldBeEmpty)
+			So(options.Algorithms, ShouldResemble, defaultAlgorithms)
+			So(options.Leeway, ShouldEqual, defaultLeeway)
+		})
+
+		Convey("Configured", func() {
+			t.Setenv("AUTH_JWT_ISSUERS", "https://a.example.com/, https://b.example.com/")
+			t.Setenv("AUTH_JWT_AUDIENCES", "go-template")
+			t.Setenv("AUTH_JWT_ALGORITHMS", "ES256")
+			t.Setenv("AUTH_JWT_LEEWAY", "5s")
+
+			options := OptionsFromEnv()
+			So(options.Issuers, ShouldResemble, []string{"https://a.example.com/", "https://b.example.com/"})
+			So(options.Audiences, ShouldResemble, []string{"go-template"})
+			So(options.Algorithms, ShouldResemble, []string{"ES256"})
+			So(options.Leeway, ShouldEqual, 5*time.Second)
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -71,11 +72,18 @@ func init() {
 func WithContext(ctx context.Context) *ContextLogger {
 	fields := activity.GetFields(ctx)
 
-	zapFields := make([]zap.Field, 0, len(fields))
+	zapFields := make([]zap.Field, 0, len(fields)+2)
 	for key, value := range fields {
 		zapFields = append(zapFields, zap.Any(key, value))
 	}
 
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		zapFields = append(zapFields,
+			zap.String("trace_id", spanCtx.TraceID().String()),
+			zap.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+
 	return &ContextLogger{Logger: logger.With(zapFields...)}
 }
 
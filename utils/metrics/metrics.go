@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"go-template/utils"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+	ResultHit     = "hit"
+	ResultMiss    = "miss"
+	ResultAcked   = "acked"
+	ResultNacked  = "nacked"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	databaseQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "database_query_duration_seconds",
+		Help:    "Database query latency by operation and result.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Total number of cache lookups by cache and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	messagePublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "message_published_total",
+		Help: "Total number of published messages by exchange and result.",
+	}, []string{"exchange", "result"})
+
+	messageConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "message_consumed_total",
+		Help: "Total number of consumed messages by queue and result (acked, nacked).",
+	}, []string{"queue", "result"})
+
+	workflowStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_started_total",
+		Help: "Total number of workflow starts by workflow and result.",
+	}, []string{"workflow", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(newHostCollector())
+}
+
+// hostCollector exports the /proc samplers in utils, sampled on every scrape
+type hostCollector struct {
+	cpuIdle  *prometheus.Desc
+	cpuTotal *prometheus.Desc
+	cpuCores *prometheus.Desc
+	memory   *prometheus.Desc
+}
+
+func newHostCollector() *hostCollector {
+	return &hostCollector{
+		cpuIdle:  prometheus.NewDesc("host_cpu_idle_ticks_total", "Idle CPU ticks reported by /proc/stat.", nil, nil),
+		cpuTotal: prometheus.NewDesc("host_cpu_ticks_total", "Total CPU ticks reported by /proc/stat.", nil, nil),
+		cpuCores: prometheus.NewDesc("host_cpu_cores", "Number of CPU cores reported by /proc/cpuinfo.", nil, nil),
+		memory:   prometheus.NewDesc("host_memory_bytes", "Host memory reported by /proc/meminfo by type.", []string{"type"}, nil),
+	}
+}
+
+func (c *hostCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuIdle
+	ch <- c.cpuTotal
+	ch <- c.cpuCores
+	ch <- c.memory
+}
+
+func (c *hostCollector) Collect(ch chan<- prometheus.Metric) {
+	idle, total := utils.GetCPUSample()
+	ch <- prometheus.MustNewConstMetric(c.cpuIdle, prometheus.CounterValue, float64(idle))
+	ch <- prometheus.MustNewConstMetric(c.cpuTotal, prometheus.CounterValue, float64(total))
+	ch <- prometheus.MustNewConstMetric(c.cpuCores, prometheus.GaugeValue, float64(utils.GetCoreSample()))
+
+	// /proc/meminfo reports kB
+	memTotal, memFree, buffers, cached := utils.GetMemorySample()
+	ch <- prometheus.MustNewConstMetric(c.memory, prometheus.GaugeValue, float64(memTotal*1024), "total")
+	ch <- prometheus.MustNewConstMetric(c.memory, prometheus.GaugeValue, float64(memFree*1024), "free")
+	ch <- prometheus.MustNewConstMetric(c.memory, prometheus.GaugeValue, float64(buffers*1024), "buffers")
+	ch <- prometheus.MustNewConstMetric(c.memory, prometheus.GaugeValue, float64(cached*1024), "cached")
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ObserveHTTPRequest(method, route string, status int, start time.Time) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+}
+
+func ObserveDatabaseQuery(operation string, start time.Time, err error) {
+	databaseQueryDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+func ObserveCacheLookup(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+func ObserveMessagePublished(exchange string, err error) {
+	messagePublished.WithLabelValues(exchange, result(err)).Inc()
+}
+
+func ObserveMessageConsumed(queue string, ack bool) {
+	if ack {
+		messageConsumed.WithLabelValues(queue, ResultAcked).Inc()
+		return
+	}
+	messageConsumed.WithLabelValues(queue, ResultNacked).Inc()
+}
+
+func ObserveWorkflowStarted(workflow string, err error) {
+	workflowStarted.WithLabelValues(workflow, result(err)).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
@@ -0,0 +1,76 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+// Config describes how the HTTP server terminates TLS. Setting ClientCAFile
+// turns on mutual TLS
+type Config struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	MinVersion     uint16
+	ClientAuth     tls.ClientAuthType
+	ReloadInterval time.Duration
+}
+
+// Enabled reports whether a server certificate is configured
+func (c Config) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// ConfigFromEnv reads SERVER_TLS_CERT_FILE, SERVER_TLS_KEY_FILE, SERVER_TLS_CLIENT_CA_FILE,
+// SERVER_TLS_MIN_VERSION, SERVER_TLS_CLIENT_AUTH and SERVER_TLS_RELOAD_INTERVAL
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		CertFile:       os.Getenv("SERVER_TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("SERVER_TLS_KEY_FILE"),
+		ClientCAFile:   os.Getenv("SERVER_TLS_CLIENT_CA_FILE"),
+		MinVersion:     tls.VersionTLS12,
+		ClientAuth:     tls.NoClientCert,
+		ReloadInterval: defaultReloadInterval,
+	}
+
+	switch os.Getenv("SERVER_TLS_MIN_VERSION") {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return Config{}, fmt.Errorf("unsupported SERVER_TLS_MIN_VERSION: %s", os.Getenv("SERVER_TLS_MIN_VERSION"))
+	}
+
+	if config.ClientCAFile != "" {
+		switch os.Getenv("SERVER_TLS_CLIENT_AUTH") {
+		case "", "require":
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			// Lets probes and bearer key callers connect without a certificate
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return Config{}, fmt.Errorf("unsupported SERVER_TLS_CLIENT_AUTH: %s", os.Getenv("SERVER_TLS_CLIENT_AUTH"))
+		}
+	}
+
+	if value := os.Getenv("SERVER_TLS_RELOAD_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid SERVER_TLS_RELOAD_INTERVAL: %w", err)
+		}
+		config.ReloadInterval = interval
+	}
+
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return Config{}, fmt.Errorf("SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+	}
+	if config.ClientCAFile != "" && !config.Enabled() {
+		return Config{}, fmt.Errorf("SERVER_TLS_CLIENT_CA_FILE requires SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE")
+	}
+
+	return config, nil
+}
@@ -0,0 +1,26 @@
+package mtls
+
+import "crypto/x509"
+
+// Identities lists the names a client certificate can be matched on, most
+// specific first: URI SANs (e.g. SPIFFE IDs), DNS SANs, email SANs, then the
+// subject common name and the full subject
+func Identities(cert *x509.Certificate) []string {
+	if cert == nil {
+		return nil
+	}
+
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	if subject := cert.Subject.String(); subject != "" {
+		identities = append(identities, subject)
+	}
+	return identities
+}
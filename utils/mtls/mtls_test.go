@@ -0,0 +1,234 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func issue(template *x509.Certificate, parent *testCert) *testCert {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template.SerialNumber, _ = rand.Int(rand.Reader, big.NewInt(1<<62))
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	So(err, ShouldBeNil)
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	keyDER, _ := x509.MarshalECPrivateKey(c.key)
+	So(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600), ShouldBeNil)
+	So(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600), ShouldBeNil)
+	return certFile, keyFile
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	Convey("Test Config From Env", t, func() {
+		Convey("Disabled by default", func() {
+			config, err := ConfigFromEnv()
+			So(err, ShouldBeNil)
+			So(config.Enabled(), ShouldBeFalse)
+			So(config.MinVersion, ShouldEqual, tls.VersionTLS12)
+			So(config.ClientAuth, ShouldEqual, tls.NoClientCert)
+		})
+
+		Convey("Mutual TLS", func() {
+			os.Setenv("SERVER_TLS_CERT_FILE", "server.crt")
+			defer os.Unsetenv("SERVER_TLS_CERT_FILE")
+			os.Setenv("SERVER_TLS_KEY_FILE", "server.key")
+			defer os.Unsetenv("SERVER_TLS_KEY_FILE")
+			os.Setenv("SERVER_TLS_CLIENT_CA_FILE", "ca.crt")
+			defer os.Unsetenv("SERVER_TLS_CLIENT_CA_FILE")
+			os.Setenv("SERVER_TLS_MIN_VERSION", "1.3")
+			defer os.Unsetenv("SERVER_TLS_MIN_VERSION")
+			os.Setenv("SERVER_TLS_RELOAD_INTERVAL", "5s")
+			defer os.Unsetenv("SERVER_TLS_RELOAD_INTERVAL")
+
+			config, err := ConfigFromEnv()
+			So(err, ShouldBeNil)
+			So(config.Enabled(), ShouldBeTrue)
+			So(config.MinVersion, ShouldEqual, tls.VersionTLS13)
+			So(config.ClientAuth, ShouldEqual, tls.RequireAndVerifyClientCert)
+			So(config.ReloadInterval, ShouldEqual, 5*time.Second)
+
+			os.Setenv("SERVER_TLS_CLIENT_AUTH", "optional")
+			defer os.Unsetenv("SERVER_TLS_CLIENT_AUTH")
+			config, err = ConfigFromEnv()
+			So(err, ShouldBeNil)
+			So(config.ClientAuth, ShouldEqual, tls.VerifyClientCertIfGiven)
+		})
+
+		Convey("Invalid settings", func() {
+			os.Setenv("SERVER_TLS_CERT_FILE", "server.crt")
+			defer os.Unsetenv("SERVER_TLS_CERT_FILE")
+			_, err := ConfigFromEnv()
+			So(err, ShouldNotBeNil)
+
+			os.Setenv("SERVER_TLS_KEY_FILE", "server.key")
+			defer os.Unsetenv("SERVER_TLS_KEY_FILE")
+			os.Setenv("SERVER_TLS_MIN_VERSION", "1.0")
+			defer os.Unsetenv("SERVER_TLS_MIN_VERSION")
+			_, err = ConfigFromEnv()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestIdentities(t *testing.T) {
+	Convey("Test Identities", t, func() {
+		spiffe, _ := url.Parse("spiffe://example.org/billing")
+		cert := &x509.Certificate{
+			URIs:           []*url.URL{spiffe},
+			DNSNames:       []string{"billing.internal"},
+			EmailAddresses: []string{"billing@example.org"},
+			Subject:        pkix.Name{CommonName: "billing", Organization: []string{"Acme"}},
+		}
+
+		So(Identities(cert), ShouldResemble, []string{
+			"spiffe://example.org/billing",
+			"billing.internal",
+			"billing@example.org",
+			"billing",
+			"CN=billing,O=Acme",
+		})
+		So(Identities(nil), ShouldBeEmpty)
+	})
+}
+
+func TestReloader(t *testing.T) {
+	Convey("Test Reloader", t, func() {
+		dir := t.TempDir()
+		ca := issue(&x509.Certificate{
+			Subject:               pkix.Name{CommonName: "test-ca"},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}, nil)
+		serverTemplate := func() *x509.Certificate {
+			return &x509.Certificate{
+				Subject:     pkix.Name{CommonName: "server"},
+				DNSNames:    []string{"localhost"},
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}
+		}
+		server := issue(serverTemplate(), ca)
+		client := issue(&x509.Certificate{
+			Subject:     pkix.Name{CommonName: "billing"},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}, ca)
+
+		certFile, keyFile := server.write(dir, "server")
+		caFile, _ := ca.write(dir, "ca")
+
+		reloader, err := NewReloader(Config{
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ClientCAFile:   caFile,
+			MinVersion:     tls.VersionTLS12,
+			ClientAuth:     tls.RequireAndVerifyClientCert,
+			ReloadInterval: 0,
+		})
+		So(err, ShouldBeNil)
+
+		var commonName string
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			commonName = r.TLS.VerifiedChains[0][0].Subject.CommonName
+		}))
+		ts.TLS = reloader.TLSConfig()
+		ts.StartTLS()
+		defer ts.Close()
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		newClient := func(certs ...tls.Certificate) *http.Client {
+			return &http.Client{Transport: &http.Transport{
+				DisableKeepAlives: true,
+				TLSClientConfig: &tls.Config{
+					RootCAs:      roots,
+					ServerName:   "localhost",
+					Certificates: certs,
+				},
+			}}
+		}
+
+		Convey("Verified client certificate reaches the handler", func() {
+			resp, err := newClient(client.tlsCertificate()).Get(ts.URL)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(commonName, ShouldEqual, "billing")
+		})
+
+		Convey("HTTP/2 is offered through ALPN on every handshake", func() {
+			conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{
+				RootCAs:      roots,
+				ServerName:   "localhost",
+				Certificates: []tls.Certificate{client.tlsCertificate()},
+				NextProtos:   []string{"h2"},
+			})
+			So(err, ShouldBeNil)
+			defer conn.Close()
+			So(conn.ConnectionState().NegotiatedProtocol, ShouldEqual, "h2")
+		})
+
+		Convey("Client without a certificate is rejected", func() {
+			_, err := newClient().Get(ts.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rotated certificate is served without a restart", func() {
+			rotated := issue(serverTemplate(), ca)
+			rotated.write(dir, "server")
+			// Make sure the modification time moves even on coarse filesystems
+			later := time.Now().Add(time.Second)
+			So(os.Chtimes(certFile, later, later), ShouldBeNil)
+
+			resp, err := newClient(client.tlsCertificate()).Get(ts.URL)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(resp.TLS.PeerCertificates[0].SerialNumber.Cmp(rotated.cert.SerialNumber), ShouldEqual, 0)
+		})
+
+		Convey("Broken files keep the previous certificate", func() {
+			So(os.WriteFile(certFile, []byte("not a certificate"), 0o600), ShouldBeNil)
+			So(reloader.Reload(), ShouldNotBeNil)
+
+			resp, err := newClient(client.tlsCertificate()).Get(ts.URL)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(resp.TLS.PeerCertificates[0].SerialNumber.Cmp(server.cert.SerialNumber), ShouldEqual, 0)
+		})
+	})
+}
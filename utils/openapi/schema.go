@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generator turns Go types into schemas. Named structs are collected in
+// Schemas and referenced by $ref, so each model is described once
+type Generator struct {
+	Schemas map[string]*Schema
+	// Rules describes custom validate tags, e.g. "bearer_key"; unknown tags are ignored
+	Rules map[string]func(s *Schema)
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		Schemas: make(map[string]*Schema),
+		Rules:   make(map[string]func(s *Schema)),
+	}
+}
+
+// Schema returns the schema of the type of v, honouring json and validate tags
+func (g *Generator) Schema(v any) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// Parameters returns a query parameter for each field of struct v with a form tag
+func (g *Generator) Parameters(v any) []*Parameter {
+	t := reflect.TypeOf(v)
+	var parameters []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		schema := g.schema(field.Type)
+		required := g.applyRules(schema, field.Tag.Get("validate"))
+		parameters = append(parameters, &Parameter{Name: name, In: "query", Required: required, Schema: schema})
+	}
+	return parameters
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := g.schema(t.Elem())
+		if typ, ok := schema.Type.(string); ok {
+			schema.Type = []string{typ, "null"}
+		}
+		return schema
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.Schemas[t.Name()]; !ok {
+			// Reserved first, so recursive types end in a $ref
+			g.Schemas[t.Name()] = &Schema{}
+			*g.Schemas[t.Name()] = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		// interfaces such as Response.Data can hold anything
+		return &Schema{}
+	}
+}
+
+func (g *Generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs are flattened, as encoding/json does
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := g.object(field.Type)
+			for property, value := range embedded.Properties {
+				schema.Properties[property] = value
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if g.applyRules(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// applyRules adds the constraints of a validate tag to s and reports whether
+// it makes the field required. Rules after dive apply to the items
+func (g *Generator) applyRules(s *Schema, tag string) bool {
+	if tag == "" || s.Ref != "" {
+		return false
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			if s.Items != nil {
+				_, rest, _ := strings.Cut(tag, "dive,")
+				g.applyRules(s.Items, rest)
+			}
+			return required
+		case "min", "max":
+			g.applyBound(s, name, param)
+		case "gt":
+			if value, err := strconv.ParseFloat(param, 64); err == nil {
+				s.ExclusiveMinimum = &value
+			}
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				s.Enum = append(s.Enum, value)
+			}
+		default:
+			if describe, ok := g.Rules[name]; ok {
+				describe(s)
+			}
+		}
+	}
+	return required
+}
+
+func (g *Generator) applyBound(s *Schema, name, param string) {
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	typ := s.Type
+	if types, ok := s.Type.([]string); ok {
+		typ = types[0]
+	}
+	switch typ {
+	case "string":
+		if name == "min" {
+			s.MinLength = &value
+		} else {
+			s.MaxLength = &value
+		}
+	case "array":
+		if name == "min" {
+			s.MinItems = &value
+		} else {
+			s.MaxItems = &value
+		}
+	case "integer", "number":
+		bound := float64(value)
+		if name == "min" {
+			s.Minimum = &bound
+		} else {
+			s.Maximum = &bound
+		}
+	}
+}
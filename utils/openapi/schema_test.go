@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type Base struct {
+	ID int64 `json:"id" validate:"gt=0"`
+}
+
+type Item struct {
+	Base
+	Name      string     `json:"name" validate:"required,max=5"`
+	Tags      []string   `json:"tags" validate:"max=3,dive,max=10"`
+	Sort      string     `json:"sort" validate:"omitempty,oneof=id -id"`
+	Key       string     `json:"key" validate:"omitempty,secret"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Child     *Item      `json:"child"`
+	Hidden    string     `json:"-"`
+}
+
+type Query struct {
+	Names []string `form:"names" validate:"dive,max=10"`
+	Limit int      `form:"limit" validate:"min=0"`
+	Keys  []string `form:"-"`
+	Other string
+}
+
+func TestGenerator(t *testing.T) {
+	Convey("Test Generator", t, func() {
+		generator := NewGenerator()
+		generator.Rules["secret"] = func(s *Schema) { s.Description = "secret" }
+
+		Convey("Named structs are referenced", func() {
+			So(generator.Schema([]Item{}), ShouldResemble, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Item"}})
+			So(generator.Schemas, ShouldContainKey, "Item")
+		})
+
+		Convey("Fields follow json and validate tags", func() {
+			generator.Schema(Item{})
+			item := generator.Schemas["Item"]
+
+			So(item.Required, ShouldResemble, []string{"name"})
+			So(item.Properties, ShouldNotContainKey, "Hidden")
+			So(*item.Properties["id"].ExclusiveMinimum, ShouldEqual, 0)
+			So(*item.Properties["name"].MaxLength, ShouldEqual, 5)
+			So(*item.Properties["tags"].MaxItems, ShouldEqual, 3)
+			So(*item.Properties["tags"].Items.MaxLength, ShouldEqual, 10)
+			So(item.Properties["sort"].Enum, ShouldResemble, []any{"id", "-id"})
+			So(item.Properties["key"].Description, ShouldEqual, "secret")
+			So(item.Properties["expires_at"], ShouldResemble, &Schema{Type: []string{"string", "null"}, Format: "date-time"})
+			So(item.Properties["child"].Ref, ShouldEqual, "#/components/schemas/Item")
+		})
+
+		Convey("Query parameters come from form tags", func() {
+			parameters := generator.Parameters(Query{})
+
+			So(parameters, ShouldHaveLength, 2)
+			So(parameters[0].Name, ShouldEqual, "names")
+			So(*parameters[0].Schema.Items.MaxLength, ShouldEqual, 10)
+			So(parameters[1].Name, ShouldEqual, "limit")
+			So(*parameters[1].Schema.Minimum, ShouldEqual, 0)
+		})
+	})
+}
@@ -5,6 +5,12 @@ import (
 	"encoding/json"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-template/utils/metrics"
+	"go-template/utils/tracing"
 )
 
 //go:generate mockgen -source=publisher.go -destination=./../../tests/mocks/mock_utils/mock_rabbitmq/mock_publisher.go
@@ -23,6 +29,22 @@ func (p *publisher) Publish(ctx context.Context, exchange string, exchangeKind E
 }
 
 func Publish(ctx context.Context, exchange string, exchangeKind ExchangeKind, routeKey string, msg any) (err error) {
+	ctx, span := tracing.Start(ctx, "publish "+exchange,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", routeKey),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveMessagePublished(exchange, err)
+	}()
+
 	msgBytes, err := json.Marshal(msg)
 	if err != nil {
 		return err
@@ -65,6 +87,7 @@ func Publish(ctx context.Context, exchange string, exchangeKind ExchangeKind, ro
 		false,
 		amqp.Publishing{
 			ContentType: "application/json",
+			Headers:     injectHeaders(ctx),
 			Body:        msgBytes,
 		})
 	if err != nil {
@@ -5,11 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"go-template/utils"
 	"go-template/utils/log"
+	"go-template/utils/metrics"
+	"go-template/utils/tracing"
 )
 
 type ExchangeKind string
@@ -21,6 +28,11 @@ const (
 	KindHeaders ExchangeKind = "headers"
 )
 
+const (
+	defaultRetryDelay       = 10 * time.Second
+	defaultRetryMaxAttempts = 5
+)
+
 var (
 	rabbitConn *amqp.Connection
 )
@@ -48,13 +60,38 @@ func InitMessage() error {
 	return nil
 }
 
+// Ping opens and closes a channel to verify the shared connection is usable
+func Ping() error {
+	if rabbitConn == nil || rabbitConn.IsClosed() {
+		return errors.New("rabbitmq connection is not open")
+	}
+	ch, err := rabbitConn.Channel()
+	if err != nil {
+		return err
+	}
+	return ch.Close()
+}
+
+func Close() error {
+	if rabbitConn == nil || rabbitConn.IsClosed() {
+		return nil
+	}
+	return rabbitConn.Close()
+}
+
 type SubscriberConfig struct {
 	Exchange     string
 	ExchangeKind ExchangeKind
 	Queue        string
 	RouteKey     string
 	ExitCount    uint
-	Callback     func(msg []byte) bool
+	Callback     func(ctx context.Context, msg []byte) bool
+	// RetryDelay is how long a nacked message waits in the retry queue
+	// before it is delivered again
+	RetryDelay time.Duration
+	// MaxAttempts is how many times a message is delivered before a nack
+	// discards it instead of retrying
+	MaxAttempts int
 }
 
 func (c *SubscriberConfig) Validate() error {
@@ -70,9 +107,35 @@ func (c *SubscriberConfig) Validate() error {
 	if c.Callback == nil {
 		return errors.New("subscriber callback empty")
 	}
+	if c.RetryDelay <= 0 {
+		return errors.New("subscriber retry delay must be positive")
+	}
+	if c.MaxAttempts < 1 {
+		return errors.New("subscriber max attempts must be at least 1")
+	}
 	return nil
 }
 
+// retryQueue holds nacked messages of queue until their delay expires
+func retryQueue(queue string) string {
+	return queue + ".retry"
+}
+
+// deliveryAttempts counts how often a message was delivered from queue,
+// from the rejections RabbitMQ records in its x-death header
+func deliveryAttempts(headers amqp.Table, queue string) int {
+	deaths, _ := headers["x-death"].([]interface{})
+	for _, death := range deaths {
+		table, ok := death.(amqp.Table)
+		if !ok || table["queue"] != queue || table["reason"] != "rejected" {
+			continue
+		}
+		count, _ := table["count"].(int64)
+		return int(count) + 1
+	}
+	return 1
+}
+
 func SubscriberWithConfig(cfg SubscriberConfig) error {
 	if err := cfg.Validate(); err != nil {
 		fmt.Printf("rabbitmq subscriber config error: %s\n", err.Error())
@@ -112,13 +175,34 @@ func SubscriberWithConfig(cfg SubscriberConfig) error {
 		return err
 	}
 
+	// Rejected messages go to the retry queue through the default exchange
+	// and come back to the queue once their delay expires
 	q, err := ch.QueueDeclare(
 		cfg.Queue,
 		true,
 		false,
 		false,
 		false,
-		nil,
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": retryQueue(cfg.Queue),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = ch.QueueDeclare(
+		retryQueue(cfg.Queue),
+		true,
+		false,
+		false,
+		false,
+		amqp.Table{
+			"x-message-ttl":             cfg.RetryDelay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": cfg.Queue,
+		},
 	)
 	if err != nil {
 		return err
@@ -152,15 +236,35 @@ func SubscriberWithConfig(cfg SubscriberConfig) error {
 	forever := make(chan struct{})
 	go func() {
 		for d := range msgs {
-			ack := cfg.Callback(d.Body)
+			msgCtx, span := tracing.Start(extractHeaders(context.Background(), d.Headers), "consume "+q.Name,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("messaging.system", "rabbitmq"),
+					attribute.String("messaging.destination.name", cfg.Exchange),
+					attribute.String("messaging.rabbitmq.queue", q.Name),
+				),
+			)
+			ack := cfg.Callback(msgCtx, d.Body)
+			if !ack {
+				span.SetStatus(codes.Error, "message nacked")
+			}
+			span.End()
+			metrics.ObserveMessageConsumed(q.Name, ack)
 			if ack {
 				err = d.Ack(false)
 				if err != nil {
 					log.WithContext(context.Background()).Error("failed to ack message", err)
 				}
 
+			} else if attempts := deliveryAttempts(d.Headers, q.Name); attempts >= cfg.MaxAttempts {
+				log.WithContext(msgCtx).Error("message discarded after retries", fmt.Errorf("queue %s: %d attempts", q.Name, attempts))
+				err = d.Ack(false)
+				if err != nil {
+					log.WithContext(context.Background()).Error("failed to ack message", err)
+				}
 			} else {
-				err = d.Nack(false, true)
+				// Without requeue the message is dead lettered to the retry queue
+				err = d.Nack(false, false)
 				if err != nil {
 					log.WithContext(context.Background()).Error("failed to nack message", err)
 				}
@@ -172,7 +276,7 @@ func SubscriberWithConfig(cfg SubscriberConfig) error {
 	return nil
 }
 
-func Subscriber(exchange string, exchangeKind ExchangeKind, queue, routeKey string, callback func(msg []byte) bool) error {
+func Subscriber(exchange string, exchangeKind ExchangeKind, queue, routeKey string, callback func(ctx context.Context, msg []byte) bool) error {
 	return SubscriberWithConfig(SubscriberConfig{
 		Exchange:     exchange,
 		ExchangeKind: exchangeKind,
@@ -180,5 +284,20 @@ func Subscriber(exchange string, exchangeKind ExchangeKind, queue, routeKey stri
 		RouteKey:     routeKey,
 		ExitCount:    0,
 		Callback:     callback,
+		RetryDelay:   retryDelay(),
+		MaxAttempts:  retryMaxAttempts(),
 	})
 }
+
+// retryDelay is MESSAGE_RETRY_DELAY or 10 seconds
+func retryDelay() time.Duration {
+	return utils.GetEnvDuration("MESSAGE_RETRY_DELAY", defaultRetryDelay)
+}
+
+// retryMaxAttempts is MESSAGE_RETRY_MAX_ATTEMPTS or 5
+func retryMaxAttempts() int {
+	if attempts := utils.GetEnvInt("MESSAGE_RETRY_MAX_ATTEMPTS", defaultRetryMaxAttempts); attempts >= 1 {
+		return attempts
+	}
+	return defaultRetryMaxAttempts
+}
@@ -0,0 +1,73 @@
+package rabbitmq
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeliveryAttempts(t *testing.T) {
+	Convey("Test delivery attempts", t, func() {
+		Convey("First delivery has no x-death header", func() {
+			So(deliveryAttempts(nil, "client.upsert"), ShouldEqual, 1)
+		})
+
+		Convey("Rejections of the queue are counted", func() {
+			headers := amqp.Table{"x-death": []interface{}{
+				amqp.Table{"queue": "client.upsert.retry", "reason": "expired", "count": int64(2)},
+				amqp.Table{"queue": "client.upsert", "reason": "rejected", "count": int64(2)},
+			}}
+			So(deliveryAttempts(headers, "client.upsert"), ShouldEqual, 3)
+		})
+
+		Convey("Rejections of other queues are ignored", func() {
+			headers := amqp.Table{"x-death": []interface{}{
+				amqp.Table{"queue": "other", "reason": "rejected", "count": int64(4)},
+			}}
+			So(deliveryAttempts(headers, "client.upsert"), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestSubscriberRetryConfig(t *testing.T) {
+	Convey("Test subscriber retry config", t, func() {
+		cfg := SubscriberConfig{
+			Exchange:     "client.upsert",
+			ExchangeKind: KindFanOut,
+			Queue:        "client.upsert.subscribe",
+			Callback:     func(context.Context, []byte) bool { return true },
+		}
+
+		Convey("Retries must be bounded", func() {
+			So(cfg.Validate(), ShouldNotBeNil)
+
+			cfg.RetryDelay = time.Second
+			So(cfg.Validate(), ShouldNotBeNil)
+
+			cfg.MaxAttempts = 3
+			So(cfg.Validate(), ShouldBeNil)
+		})
+
+		Convey("Defaults apply when the environment is unset or invalid", func() {
+			os.Setenv("MESSAGE_RETRY_DELAY", "soon")
+			defer os.Unsetenv("MESSAGE_RETRY_DELAY")
+
+			So(retryDelay(), ShouldEqual, defaultRetryDelay)
+			So(retryMaxAttempts(), ShouldEqual, defaultRetryMaxAttempts)
+		})
+
+		Convey("Environment overrides the defaults", func() {
+			os.Setenv("MESSAGE_RETRY_DELAY", "30s")
+			defer os.Unsetenv("MESSAGE_RETRY_DELAY")
+			os.Setenv("MESSAGE_RETRY_MAX_ATTEMPTS", "2")
+			defer os.Unsetenv("MESSAGE_RETRY_MAX_ATTEMPTS")
+
+			So(retryDelay(), ShouldEqual, 30*time.Second)
+			So(retryMaxAttempts(), ShouldEqual, 2)
+		})
+	})
+}
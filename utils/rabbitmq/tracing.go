@@ -0,0 +1,56 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"go-template/utils/activity"
+	"go-template/utils/tracing"
+)
+
+// TransactionIDHeader carries the activity transaction ID between services
+const TransactionIDHeader = "x-transaction-id"
+
+// headerCarrier adapts AMQP headers to the OpenTelemetry TextMapCarrier interface
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, ok := c[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// injectHeaders writes the trace context and transaction ID from ctx into AMQP headers
+func injectHeaders(ctx context.Context) amqp.Table {
+	headers := amqp.Table{}
+	tracing.Inject(ctx, headerCarrier(headers))
+	if trxID, ok := activity.GetTransactionID(ctx); ok {
+		headers[TransactionIDHeader] = trxID
+	}
+	return headers
+}
+
+// extractHeaders restores the trace context and transaction ID carried in AMQP headers
+func extractHeaders(ctx context.Context, headers amqp.Table) context.Context {
+	ctx = tracing.Extract(ctx, headerCarrier(headers))
+	if trxID := headerCarrier(headers).Get(TransactionIDHeader); trxID != "" {
+		ctx = activity.WithTransactionID(ctx, trxID)
+	}
+	return ctx
+}
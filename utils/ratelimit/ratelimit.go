@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one request against a sliding window limit
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Window splits time into fixed windows and returns the index of the current
+// one and how far into it now is
+func Window(now time.Time, window time.Duration) (int64, time.Duration) {
+	index := now.UnixNano() / int64(window)
+	return index, time.Duration(now.UnixNano() - index*int64(window))
+}
+
+// Weight is the share of the previous window still inside the sliding window
+func Weight(elapsed, window time.Duration) float64 {
+	return float64(window-elapsed) / float64(window)
+}
+
+// Evaluate turns the counters of the previous and current window, read after
+// the request was counted or refused, into headers worth of information
+func Evaluate(previous, current int64, allowed bool, limit int, window, elapsed time.Duration) Result {
+	weight := Weight(elapsed, window)
+	estimate := float64(previous)*weight + float64(current)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(float64(limit) - estimate),
+		// The estimate is back to the current window alone once it ends
+		ResetAfter: window - elapsed,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !allowed {
+		result.RetryAfter = retryAfter(previous, current, limit, window, elapsed)
+	}
+	return result
+}
+
+// retryAfter is how long until one more request fits under the limit
+func retryAfter(previous, current int64, limit int, window, elapsed time.Duration) time.Duration {
+	room := float64(limit) - float64(current) - 1
+	if room >= 0 && previous > 0 {
+		// Wait for the previous window to decay enough within the current one
+		wait := float64(window-elapsed) - room*float64(window)/float64(previous)
+		if wait < 0 {
+			wait = 0
+		}
+		return time.Duration(wait)
+	}
+
+	// The current window alone is full: it becomes the previous one next window
+	wait := window - elapsed
+	if current > 0 {
+		decay := float64(window) - float64(limit-1)*float64(window)/float64(current)
+		if decay > 0 {
+			wait += time.Duration(decay)
+		}
+	}
+	return wait
+}
+
+// Seconds rounds up to whole seconds, never below one, as the RateLimit-Reset
+// and Retry-After header values require
+func Seconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// Memory is a process local sliding window limiter for tests and single instance runs
+type Memory struct {
+	mu      sync.Mutex
+	windows map[string]memoryWindow
+	sweptAt int64
+}
+
+type memoryWindow struct {
+	index    int64
+	previous int64
+	current  int64
+}
+
+func NewMemory() *Memory {
+	return &Memory{windows: make(map[string]memoryWindow)}
+}
+
+// Allow counts the request against key when it fits under limit per window
+func (m *Memory) Allow(key string, limit int, window time.Duration, now time.Time) Result {
+	index, elapsed := Window(now, window)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter := m.windows[key]
+	switch {
+	case counter.index == index:
+	case counter.index == index-1:
+		counter = memoryWindow{index: index, previous: counter.current}
+	default:
+		counter = memoryWindow{index: index}
+	}
+
+	allowed := float64(counter.previous)*Weight(elapsed, window)+float64(counter.current)+1 <= float64(limit)
+	if allowed {
+		counter.current++
+	}
+	m.windows[key] = counter
+	m.sweep(index)
+
+	return Evaluate(counter.previous, counter.current, allowed, limit, window, elapsed)
+}
+
+// sweep drops counters that no longer affect any decision, at most once per window
+func (m *Memory) sweep(index int64) {
+	if m.sweptAt == index {
+		return
+	}
+	m.sweptAt = index
+	for key, counter := range m.windows {
+		if counter.index < index-1 {
+			delete(m.windows, key)
+		}
+	}
+}
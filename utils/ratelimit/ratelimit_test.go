@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMemory(t *testing.T) {
+	Convey("Test Memory", t, func() {
+		limiter := NewMemory()
+		window := time.Minute
+		start := time.Unix(0, 0).Add(100 * window)
+
+		Convey("Allows up to the limit within a window", func() {
+			for i := 0; i < 3; i++ {
+				result := limiter.Allow("client", 3, window, start.Add(time.Duration(i)*time.Second))
+				So(result.Allowed, ShouldBeTrue)
+				So(result.Remaining, ShouldEqual, 2-i)
+			}
+
+			result := limiter.Allow("client", 3, window, start.Add(5*time.Second))
+			So(result.Allowed, ShouldBeFalse)
+			So(result.Remaining, ShouldEqual, 0)
+			So(result.RetryAfter, ShouldBeGreaterThan, 0)
+			So(result.ResetAfter, ShouldEqual, 55*time.Second)
+		})
+
+		Convey("Keys are counted separately", func() {
+			So(limiter.Allow("a", 1, window, start).Allowed, ShouldBeTrue)
+			So(limiter.Allow("a", 1, window, start).Allowed, ShouldBeFalse)
+			So(limiter.Allow("b", 1, window, start).Allowed, ShouldBeTrue)
+		})
+
+		Convey("The previous window decays across the current one", func() {
+			for i := 0; i < 4; i++ {
+				So(limiter.Allow("client", 4, window, start).Allowed, ShouldBeTrue)
+			}
+
+			// A quarter into the next window three quarters of the old count still apply
+			result := limiter.Allow("client", 4, window, start.Add(window+15*time.Second))
+			So(result.Allowed, ShouldBeTrue)
+			So(limiter.Allow("client", 4, window, start.Add(window+15*time.Second)).Allowed, ShouldBeFalse)
+
+			// Two windows later nothing is left
+			result = limiter.Allow("client", 4, window, start.Add(3*window))
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Remaining, ShouldEqual, 3)
+		})
+
+		Convey("RetryAfter points at the moment a request fits again", func() {
+			So(limiter.Allow("client", 1, window, start).Allowed, ShouldBeTrue)
+			denied := limiter.Allow("client", 1, window, start.Add(30*time.Second))
+			So(denied.Allowed, ShouldBeFalse)
+
+			So(limiter.Allow("client", 1, window, start.Add(30*time.Second+denied.RetryAfter-time.Second)).Allowed, ShouldBeFalse)
+			So(limiter.Allow("client", 1, window, start.Add(30*time.Second+denied.RetryAfter)).Allowed, ShouldBeTrue)
+		})
+	})
+}
+
+func TestSeconds(t *testing.T) {
+	Convey("Test Seconds", t, func() {
+		So(Seconds(90*time.Second), ShouldEqual, 90)
+		So(Seconds(1500*time.Millisecond), ShouldEqual, 2)
+		So(Seconds(0), ShouldEqual, 1)
+	})
+}
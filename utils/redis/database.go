@@ -2,7 +2,9 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"os"
+	"time"
 
 	redis "github.com/redis/go-redis/v9"
 )
@@ -26,10 +28,77 @@ func Set(ctx context.Context, key string, value interface{}) error {
 	return dbClient.Set(ctx, key, value, 24*60*60*1e9).Err() // 1 day in nanoseconds
 }
 
+// SetWithTTL stores the value for ttl instead of the default one day
+func SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return dbClient.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores the value only when the key does not exist yet, reporting whether it did
+func SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return dbClient.SetNX(ctx, key, value, ttl).Result()
+}
+
+// RunScript evaluates a Lua script, loading it on the server the first time
+func RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	return script.Run(ctx, dbClient, keys, args...).Result()
+}
+
+// incrScript sets the expiry only on the first increment, so the count covers
+// a fixed period starting with it
+var incrScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// IncrWithTTL increments the counter at key, which expires ttl after it was created
+func IncrWithTTL(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, dbClient, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+// IncrRefreshTTL increments the counter at key and restarts its ttl
+func IncrRefreshTTL(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := dbClient.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.PExpire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// TTL returns how long key lives on, zero when it does not exist or never expires
+func TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := dbClient.PTTL(ctx, key).Result()
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
+
 func Get(ctx context.Context, key string) (string, error) {
 	return dbClient.Get(ctx, key).Result()
 }
 
-func Del(ctx context.Context, key string) error {
-	return dbClient.Del(ctx, key).Err()
+func Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return dbClient.Del(ctx, keys...).Err()
+}
+
+func Ping(ctx context.Context) error {
+	if dbClient == nil {
+		return errors.New("redis client is not initialized")
+	}
+	return dbClient.Ping(ctx).Err()
+}
+
+func Close() error {
+	if dbClient == nil {
+		return nil
+	}
+	return dbClient.Close()
 }
@@ -0,0 +1,44 @@
+package secretbox
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSecretbox(t *testing.T) {
+	Convey("Test Secretbox", t, func() {
+		Convey("Without a key", func() {
+			_, err := Seal("signing-secret")
+			So(err, ShouldEqual, ErrNoKey)
+		})
+
+		Convey("With a key", func() {
+			os.Setenv("SECRET_ENCRYPTION_KEY", "test-encryption-key")
+			defer os.Unsetenv("SECRET_ENCRYPTION_KEY")
+
+			sealed, err := Seal("signing-secret")
+			So(err, ShouldBeNil)
+			So(sealed, ShouldNotContainSubstring, "signing-secret")
+
+			again, _ := Seal("signing-secret")
+			So(again, ShouldNotEqual, sealed)
+
+			plaintext, err := Open(sealed)
+			So(err, ShouldBeNil)
+			So(plaintext, ShouldEqual, "signing-secret")
+
+			Convey("Tampered value", func() {
+				_, err := Open(sealed[:len(sealed)-2] + "AA")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Another key", func() {
+				os.Setenv("SECRET_ENCRYPTION_KEY", "rotated-key")
+				_, err := Open(sealed)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	ClientIDHeader  = "X-Client-ID"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+// CanonicalString is what the client signs: one field per line, with the body
+// reduced to its hex SHA-256 so large payloads do not change the layout
+func CanonicalString(method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// Sign returns the hex HMAC-SHA256 of the canonical string under the client secret
+func Sign(secret, method, requestURI, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(CanonicalString(method, requestURI, timestamp, nonce, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify compares the presented signature in constant time
+func Verify(secret, method, requestURI, timestamp, nonce string, body []byte, presented string) bool {
+	expected := Sign(secret, method, requestURI, timestamp, nonce, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(presented)))
+}
@@ -0,0 +1,32 @@
+package signature
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSignature(t *testing.T) {
+	Convey("Test Signature", t, func() {
+		body := []byte(`{"name":"billing"}`)
+		signed := Sign("secret", "post", "/v1/ping?x=1", "1700000000", "n-1", body)
+
+		Convey("Canonical string layout", func() {
+			So(CanonicalString("post", "/v1/ping?x=1", "1700000000", "n-1", nil), ShouldEqual,
+				"POST\n/v1/ping?x=1\n1700000000\nn-1\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+		})
+
+		Convey("Matching signature", func() {
+			So(Verify("secret", "POST", "/v1/ping?x=1", "1700000000", "n-1", body, signed), ShouldBeTrue)
+		})
+
+		Convey("Any changed field breaks the signature", func() {
+			So(Verify("other", "POST", "/v1/ping?x=1", "1700000000", "n-1", body, signed), ShouldBeFalse)
+			So(Verify("secret", "GET", "/v1/ping?x=1", "1700000000", "n-1", body, signed), ShouldBeFalse)
+			So(Verify("secret", "POST", "/v1/ping?x=2", "1700000000", "n-1", body, signed), ShouldBeFalse)
+			So(Verify("secret", "POST", "/v1/ping?x=1", "1700000001", "n-1", body, signed), ShouldBeFalse)
+			So(Verify("secret", "POST", "/v1/ping?x=1", "1700000000", "n-2", body, signed), ShouldBeFalse)
+			So(Verify("secret", "POST", "/v1/ping?x=1", "1700000000", "n-1", []byte(`{}`), signed), ShouldBeFalse)
+		})
+	})
+}
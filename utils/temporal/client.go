@@ -0,0 +1,31 @@
+package temporal
+
+import (
+	"fmt"
+	"os"
+
+	"go.temporal.io/sdk/client"
+	opentelemetry "go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+// clientOptions builds the options shared by workflow starters, workers and health checks.
+// Trace context and the activity transaction ID travel in Temporal headers.
+func clientOptions(namespace string) (client.Options, error) {
+	tracingInterceptor, err := opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{})
+	if err != nil {
+		return client.Options{}, fmt.Errorf("failed to create tracing interceptor: %w", err)
+	}
+
+	return client.Options{
+		HostPort:           getHostPort(),
+		Namespace:          namespace,
+		Interceptors:       []interceptor.ClientInterceptor{tracingInterceptor},
+		ContextPropagators: []workflow.ContextPropagator{newTransactionPropagator()},
+	}, nil
+}
+
+func getHostPort() string {
+	return fmt.Sprintf("%s:%s", os.Getenv("WORKFLOW_HOST"), os.Getenv("WORKFLOW_PORT"))
+}
@@ -0,0 +1,27 @@
+package temporal
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/sdk/client"
+)
+
+// CheckHealth dials the Temporal frontend and runs its health check
+func CheckHealth(ctx context.Context) error {
+	c, err := client.DialContext(ctx, client.Options{
+		HostPort:  getHostPort(),
+		Namespace: getNamespace(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dial temporal client: %w", err)
+	}
+	defer c.Close()
+
+	_, err = c.CheckHealth(ctx, &client.CheckHealthRequest{})
+	if err != nil {
+		return fmt.Errorf("temporal health check failed: %w", err)
+	}
+
+	return nil
+}
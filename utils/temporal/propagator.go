@@ -0,0 +1,74 @@
+package temporal
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+
+	"go-template/utils/activity"
+)
+
+// transactionIDHeader carries the activity transaction ID in Temporal headers
+const transactionIDHeader = "transaction-id"
+
+// transactionPropagator passes the activity transaction ID from the caller
+// into workflows and from workflows into their activities
+type transactionPropagator struct{}
+
+func newTransactionPropagator() workflow.ContextPropagator {
+	return &transactionPropagator{}
+}
+
+func (p *transactionPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	trxID, ok := activity.GetTransactionID(ctx)
+	if !ok {
+		return nil
+	}
+	return p.write(trxID, writer)
+}
+
+func (p *transactionPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	trxID, ok := ctx.Value(activity.TransactionID).(string)
+	if !ok {
+		return nil
+	}
+	return p.write(trxID, writer)
+}
+
+func (p *transactionPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	trxID, err := p.read(reader)
+	if err != nil || trxID == "" {
+		return ctx, err
+	}
+	return activity.WithTransactionID(ctx, trxID), nil
+}
+
+func (p *transactionPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	trxID, err := p.read(reader)
+	if err != nil || trxID == "" {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, activity.TransactionID, trxID), nil
+}
+
+func (p *transactionPropagator) write(trxID string, writer workflow.HeaderWriter) error {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(trxID)
+	if err != nil {
+		return err
+	}
+	writer.Set(transactionIDHeader, payload)
+	return nil
+}
+
+func (p *transactionPropagator) read(reader workflow.HeaderReader) (string, error) {
+	payload, ok := reader.Get(transactionIDHeader)
+	if !ok {
+		return "", nil
+	}
+	var trxID string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &trxID); err != nil {
+		return "", err
+	}
+	return trxID, nil
+}
@@ -20,19 +20,20 @@ type WorkerConfig struct {
 }
 
 func NewWorker(ctx context.Context, name string) (worker.Worker, error) {
-	hostPort := fmt.Sprintf("%s:%s", os.Getenv("WORKFLOW_HOST"), os.Getenv("WORKFLOW_PORT"))
 	namespace := getNamespace()
 
 	// Ensure namespace exists with proper error handling
-	err := ensureNamespaceExists(ctx, hostPort, namespace)
+	err := ensureNamespaceExists(ctx, getHostPort(), namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ensure namespace exists: %w", err)
 	}
 
-	c, err := client.Dial(client.Options{
-		HostPort:  hostPort,
-		Namespace: namespace,
-	})
+	options, err := clientOptions(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.Dial(options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial temporal client: %w", err)
 	}
@@ -3,25 +3,29 @@ package temporal
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/pborman/uuid"
 	"go.temporal.io/sdk/client"
+
+	"go-template/utils/activity"
+	"go-template/utils/metrics"
 )
 
-func ExecuteWorkflow(ctx context.Context, namespace, name string, input interface{}) (client.WorkflowRun, error) {
-	hostPort := fmt.Sprintf("%s:%s", os.Getenv("WORKFLOW_HOST"), os.Getenv("WORKFLOW_PORT"))
+func ExecuteWorkflow(ctx context.Context, namespace, name string, input interface{}) (run client.WorkflowRun, err error) {
+	defer func() { metrics.ObserveWorkflowStarted(name, err) }()
 
 	// Ensure namespace exists with proper error handling
-	err := ensureNamespaceExists(ctx, hostPort, namespace)
+	err = ensureNamespaceExists(ctx, getHostPort(), namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ensure namespace exists: %w", err)
 	}
 
-	c, err := client.Dial(client.Options{
-		HostPort:  hostPort,
-		Namespace: namespace,
-	})
+	options, err := clientOptions(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.Dial(options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial temporal client: %w", err)
 	}
@@ -31,5 +35,12 @@ func ExecuteWorkflow(ctx context.Context, namespace, name string, input interfac
 		TaskQueue: name,
 	}
 
+	// Record the transaction ID in the memo so it is visible on the execution itself
+	if trxID, ok := activity.GetTransactionID(ctx); ok {
+		workflowOptions.Memo = map[string]interface{}{
+			"transaction_id": trxID,
+		}
+	}
+
 	return c.ExecuteWorkflow(ctx, workflowOptions, name, input)
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func GetCPUSample() (idle, total uint64) {
@@ -96,6 +97,25 @@ func IsInList(list []string, s string) bool {
 	return false
 }
 
+// GetEnvInt reads name as an integer, fallback when it is unset or invalid
+func GetEnvInt(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// GetEnvDuration reads name as a duration, fallback when it is unset, invalid
+// or not positive
+func GetEnvDuration(name string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
 func GetDatabaseString() string {
 	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s&connect_timeout=5",
 		os.Getenv("OUTBOUND_DATABASE_DRIVER"),
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetEnv(t *testing.T) {
+	Convey("Test Get Env", t, func() {
+		defer os.Unsetenv("TEST_ENV_VALUE")
+
+		Convey("Unset values fall back", func() {
+			os.Unsetenv("TEST_ENV_VALUE")
+
+			So(GetEnvInt("TEST_ENV_VALUE", 10), ShouldEqual, 10)
+			So(GetEnvDuration("TEST_ENV_VALUE", time.Minute), ShouldEqual, time.Minute)
+		})
+
+		Convey("Invalid values fall back", func() {
+			os.Setenv("TEST_ENV_VALUE", "ten")
+
+			So(GetEnvInt("TEST_ENV_VALUE", 10), ShouldEqual, 10)
+			So(GetEnvDuration("TEST_ENV_VALUE", time.Minute), ShouldEqual, time.Minute)
+		})
+
+		Convey("Integers keep zero, durations must be positive", func() {
+			os.Setenv("TEST_ENV_VALUE", "0")
+			So(GetEnvInt("TEST_ENV_VALUE", 10), ShouldEqual, 0)
+
+			os.Setenv("TEST_ENV_VALUE", "0s")
+			So(GetEnvDuration("TEST_ENV_VALUE", time.Minute), ShouldEqual, time.Minute)
+
+			os.Setenv("TEST_ENV_VALUE", "90s")
+			So(GetEnvDuration("TEST_ENV_VALUE", time.Minute), ShouldEqual, 90*time.Second)
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"go-template/utils/apikey"
+	"go-template/utils/apperror"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by the names callers send them as
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	_ = v.RegisterValidation("bearer_key", func(fl validator.FieldLevel) bool {
+		return apikey.Strong(fl.Field().String())
+	})
+	return v
+}
+
+// Struct checks the validate tags of v. Failures are returned as a validation
+// error carrying one apperror.FieldError per invalid field
+func Struct(v interface{}) error {
+	fields := check(v, "")
+	if len(fields) > 0 {
+		return apperror.NewInvalidFields(fields)
+	}
+	return nil
+}
+
+// Slice checks every item like Struct, prefixing field names with the item
+// index, e.g. "[1].name", so batches report every invalid item at once
+func Slice[T any](items []T) error {
+	var fields []apperror.FieldError
+	for i, item := range items {
+		fields = append(fields, check(item, "["+strconv.Itoa(i)+"].")...)
+	}
+	if len(fields) > 0 {
+		return apperror.NewInvalidFields(fields)
+	}
+	return nil
+}
+
+func check(v interface{}, prefix string) []apperror.FieldError {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		// Only a nil or non struct value gets here, which is a programming error
+		panic(err)
+	}
+
+	fields := make([]apperror.FieldError, len(invalid))
+	for i, fe := range invalid {
+		fields[i] = apperror.FieldError{
+			Field:   prefix + field(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		}
+	}
+	return fields
+}
+
+// field drops the struct name validator puts in front of the namespace
+func field(namespace string) string {
+	if _, rest, ok := strings.Cut(namespace, "."); ok {
+		return rest
+	}
+	return namespace
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "bearer_key":
+		return fmt.Sprintf("must be %d to %d printable characters without spaces, using at least %d different characters",
+			apikey.MinLength, apikey.MaxLength, apikey.MinDistinct)
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters", bound, fe.Param())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("must have %s %s items", bound, fe.Param())
+		default:
+			return fmt.Sprintf("must be %s %s", bound, fe.Param())
+		}
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return "is invalid"
+	}
+}
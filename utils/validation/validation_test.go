@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"go-template/utils/apperror"
+)
+
+type input struct {
+	Name      string   `json:"name" validate:"required,max=5"`
+	BearerKey string   `json:"bearer_key,omitempty" validate:"omitempty,bearer_key"`
+	Tags      []string `json:"tags" validate:"dive,max=3"`
+	Limit     int      `json:"limit" validate:"min=0"`
+	Sort      string   `json:"sort" validate:"omitempty,oneof=id -id"`
+	Hidden    string   `json:"-" validate:"max=1"`
+}
+
+func TestValidation(t *testing.T) {
+	Convey("Test Validation", t, func() {
+		Convey("Valid input passes", func() {
+			So(Struct(input{Name: "ok"}), ShouldBeNil)
+			So(Slice([]input{{Name: "a"}, {Name: "b"}}), ShouldBeNil)
+		})
+
+		Convey("Fields are reported by their JSON names", func() {
+			err := Struct(input{Name: "too long", Tags: []string{"ok", "long"}, Limit: -1})
+
+			So(apperror.Code(err), ShouldEqual, apperror.Validation)
+			So(apperror.Details(err), ShouldResemble, []apperror.FieldError{
+				{Field: "name", Rule: "max", Message: "must be at most 5 characters"},
+				{Field: "tags[1]", Rule: "max", Message: "must be at most 3 characters"},
+				{Field: "limit", Rule: "min", Message: "must be at least 0"},
+			})
+			So(apperror.Message(err), ShouldEqual, "name must be at most 5 characters; tags[1] must be at most 3 characters; limit must be at least 0")
+		})
+
+		Convey("Choices are listed", func() {
+			err := Struct(input{Name: "ok", Sort: "name"})
+
+			So(apperror.Message(err), ShouldEqual, "sort must be one of id, -id")
+		})
+
+		Convey("Slices report every invalid item by index", func() {
+			err := Slice([]input{{Name: "ok"}, {}, {Name: "ok"}, {Name: "toolong"}})
+
+			details := apperror.Details(err)
+			So(details, ShouldHaveLength, 2)
+			So(details[0].Field, ShouldEqual, "[1].name")
+			So(details[0].Rule, ShouldEqual, "required")
+			So(details[1].Field, ShouldEqual, "[3].name")
+		})
+
+		Convey("Bearer keys must be strong", func() {
+			weak := []string{
+				"short-key",
+				strings.Repeat("ab", 20),
+				"Kx7-pQ2m_Vr9sLt4 Wz8nBy3cHd6fJg5a",
+				"Kx7-pQ2m_Vr9sLt4Wz8nBy3cHd6fJg5é",
+				strings.Repeat("Kx7-pQ2m_Vr9sLt4", 17),
+			}
+			for _, key := range weak {
+				err := Struct(input{Name: "ok", BearerKey: key})
+				So(apperror.Details(err), ShouldHaveLength, 1)
+				So(apperror.Details(err)[0].Rule, ShouldEqual, "bearer_key")
+			}
+
+			So(Struct(input{Name: "ok", BearerKey: "Kx7-pQ2m_Vr9sLt4Wz8nBy3cHd6fJg5a"}), ShouldBeNil)
+			So(Struct(input{Name: "ok", BearerKey: strings.Repeat("0123456789abcdef", 4)}), ShouldBeNil)
+		})
+	})
+}